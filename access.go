@@ -0,0 +1,112 @@
+package pager
+
+import (
+	"slices"
+	"sync"
+)
+
+// maxTrackedPages caps the number of pages tracked by WithAccessTracking().
+const maxTrackedPages = 1 << 16
+
+// PageStat holds the access counts of a single page.
+type PageStat struct {
+	ID     uint64
+	Reads  uint64
+	Writes uint64
+}
+
+// accessTracker counts reads and writes per page. The number of tracked
+// pages is capped: when the cap is reached all counts are halved and pages
+// dropping to zero are forgotten, so the cold pages make room for new ones.
+type accessTracker struct {
+	mu    sync.Mutex
+	pages map[uint64]*PageStat
+}
+
+func newAccessTracker() *accessTracker {
+	return &accessTracker{pages: map[uint64]*PageStat{}}
+}
+
+func (at *accessTracker) track(id uint64, write bool) {
+	at.mu.Lock()
+	defer at.mu.Unlock()
+
+	stat, ok := at.pages[id]
+	if !ok {
+		if len(at.pages) >= maxTrackedPages {
+			at.decay()
+			if len(at.pages) >= maxTrackedPages {
+				return
+			}
+		}
+		stat = &PageStat{ID: id}
+		at.pages[id] = stat
+	}
+
+	if write {
+		stat.Writes++
+	} else {
+		stat.Reads++
+	}
+}
+
+func (at *accessTracker) decay() {
+	for id, stat := range at.pages {
+		stat.Reads /= 2
+		stat.Writes /= 2
+		if stat.Reads == 0 && stat.Writes == 0 {
+			delete(at.pages, id)
+		}
+	}
+}
+
+func (at *accessTracker) hottest(topN int) []PageStat {
+	if topN <= 0 {
+		return []PageStat{}
+	}
+
+	at.mu.Lock()
+	stats := make([]PageStat, 0, len(at.pages))
+	for _, stat := range at.pages {
+		stats = append(stats, *stat)
+	}
+	at.mu.Unlock()
+
+	slices.SortFunc(stats, func(a, b PageStat) int {
+		if a, b := a.Reads+a.Writes, b.Reads+b.Writes; a != b {
+			if a > b {
+				return -1
+			}
+			return 1
+		}
+		if a.ID < b.ID {
+			return -1
+		} else if a.ID > b.ID {
+			return 1
+		}
+		return 0
+	})
+
+	if topN < len(stats) {
+		stats = stats[:topN]
+	}
+	return stats
+}
+
+// HotPages returns the 'topN' most accessed pages, ordered by the total
+// number of reads and writes, none for non-positive 'topN'. Returns nil
+// unless WithAccessTracking() is set.
+// Once many pages are tracked the counts are periodically halved, so they
+// are relative rather than exact for large files.
+func (p *Pager) HotPages(topN int) []PageStat {
+	if p.access == nil {
+		return nil
+	}
+	return p.access.hottest(topN)
+}
+
+func (p *Pager) trackAccess(id uint64, write bool) {
+	if p.access != nil {
+		p.access.track(id, write)
+	}
+}
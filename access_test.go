@@ -0,0 +1,45 @@
+package pager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_HotPages(t *testing.T) {
+	p := OpenNop(16, WithAccessTracking())
+	defer p.Close()
+
+	_, err := p.Alloc(3)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := p.Read(2)
+		require.NoError(t, err)
+	}
+	require.NoError(t, p.Write(0, []byte{1}))
+	_, err = p.Read(0)
+	require.NoError(t, err)
+
+	require.Equal(t, []PageStat{
+		{ID: 2, Reads: 3},
+		{ID: 0, Reads: 1, Writes: 1},
+	}, p.HotPages(10))
+	require.Len(t, p.HotPages(1), 1)
+	require.Empty(t, p.HotPages(0))
+	require.Empty(t, p.HotPages(-1))
+
+	require.Nil(t, OpenNop(16).HotPages(1))
+}
+
+func TestAccessTracker_Cap(t *testing.T) {
+	at := newAccessTracker()
+	at.track(0, false)
+	at.track(0, false)
+	for id := uint64(1); id < maxTrackedPages+1; id++ {
+		at.track(id, false)
+	}
+
+	require.Len(t, at.pages, 2)
+	require.Equal(t, uint64(1), at.pages[0].Reads)
+}
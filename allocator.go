@@ -0,0 +1,327 @@
+package pager
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+)
+
+var (
+	_ Allocator = (*TruncateAllocator)(nil)
+	_ Allocator = (*FreeListAllocator)(nil)
+	_ Allocator = (*BitmapAllocator)(nil)
+
+	_ FreeTracker = (*FreeListAllocator)(nil)
+	_ FreeTracker = (*BitmapAllocator)(nil)
+)
+
+// ErrDoubleFree is returned by allocators when freeing a page that is
+// already free.
+var ErrDoubleFree = errors.New("page is already free")
+
+// Allocator decides which pages are handed out by Pager.Alloc and what
+// happens to pages released by Pager.Free and Pager.FreePages. Allocators are
+// called with the pager lock held and must not call back into the pager.
+type Allocator interface {
+	// Alloc returns the ids of 'n' sequential pages ready for use.
+	Alloc(n int) ([]uint64, error)
+
+	// Free releases the pages with given ids. The ids are guaranteed to be
+	// less than the page count.
+	Free(ids []uint64) error
+}
+
+// FreeTracker is implemented by allocators that keep track of free pages,
+// exposing them to the scavenger (see WithScavenger) and Pager.TrimTail.
+type FreeTracker interface {
+	// FreeRuns returns the sequential runs of free pages in ascending order.
+	FreeRuns() []PageRun
+
+	// TrimTail removes the free pages at the end of the file from both the
+	// allocator and the file, returning the number of removed pages.
+	TrimTail() (int, error)
+}
+
+// PageRun is a sequence of 'Len' pages starting from the page 'ID'.
+type PageRun struct {
+	ID  uint64
+	Len int
+}
+
+// PageSpace is the file growth primitive allocators are built on.
+type PageSpace interface {
+	// Count returns the number of pages in the file.
+	Count() uint64
+
+	// Grow appends 'n' pages to the end of the file and returns the id of
+	// the first one.
+	Grow(n int) (uint64, error)
+
+	// Shrink removes 'n' pages from the end of the file.
+	Shrink(n int) error
+}
+
+// pageSpace implements PageSpace over a pager with its lock already held.
+type pageSpace struct {
+	p *Pager
+}
+
+func (ps pageSpace) Count() uint64              { return ps.p.count }
+func (ps pageSpace) Grow(n int) (uint64, error) { return ps.p.grow(n) }
+func (ps pageSpace) Shrink(n int) error         { return ps.p.shrink(n) }
+
+// TruncateAllocator always grows the file to allocate pages and truncates it
+// to free them, so only the pages at the end of the file can be freed. It's
+// the default allocator.
+type TruncateAllocator struct {
+	space PageSpace
+}
+
+// NewTruncateAllocator returns a TruncateAllocator for given page space.
+func NewTruncateAllocator(space PageSpace) Allocator {
+	return &TruncateAllocator{space: space}
+}
+
+func (ta *TruncateAllocator) Alloc(n int) ([]uint64, error) {
+	first, err := ta.space.Grow(n)
+	if err != nil {
+		return nil, err
+	}
+	return sequence(first, n), nil
+}
+
+func (ta *TruncateAllocator) Free(ids []uint64) error {
+	sorted := append([]uint64{}, ids...)
+	slices.Sort(sorted)
+
+	count := ta.space.Count()
+	for i, id := range sorted {
+		if id != count-uint64(len(sorted)-i) {
+			return fmt.Errorf("page %d is not at the end of the file", id)
+		}
+	}
+	return ta.space.Shrink(len(sorted))
+}
+
+// FreeListAllocator keeps the freed pages in a sorted list of extents and
+// reuses them for later allocations, the file is only grown when no extent
+// is long enough. Freeing a page adjacent to an extent extends it, merging
+// the extents on both sides if needed, so the list holds as few extents as
+// possible. A free extent at the end of the file is extended by growing the
+// file as needed.
+//
+// The free list lives in memory only and is lost when the pager is closed,
+// the freed pages are then seen as allocated after reopening.
+type FreeListAllocator struct {
+	space PageSpace
+	free  []PageRun
+	count int
+}
+
+// NewFreeListAllocator returns a FreeListAllocator for given page space.
+func NewFreeListAllocator(space PageSpace) Allocator {
+	return &FreeListAllocator{space: space}
+}
+
+func (fa *FreeListAllocator) Alloc(n int) ([]uint64, error) {
+	for i, run := range fa.free {
+		if run.Len >= n {
+			fa.take(i, n)
+			return sequence(run.ID, n), nil
+		} else if i == len(fa.free)-1 && run.ID+uint64(run.Len) == fa.space.Count() {
+			// the last extent reaches the end of the file, grow it
+			if _, err := fa.space.Grow(n - run.Len); err != nil {
+				return nil, err
+			}
+			fa.take(i, run.Len)
+			return sequence(run.ID, n), nil
+		}
+	}
+
+	first, err := fa.space.Grow(n)
+	if err != nil {
+		return nil, err
+	}
+	return sequence(first, n), nil
+}
+
+// take removes the first 'n' pages of the extent at index 'i'.
+func (fa *FreeListAllocator) take(i, n int) {
+	fa.free[i].ID += uint64(n)
+	fa.free[i].Len -= n
+	fa.count -= n
+	if fa.free[i].Len == 0 {
+		fa.free = slices.Delete(fa.free, i, i+1)
+	}
+}
+
+func (fa *FreeListAllocator) Free(ids []uint64) error {
+	for _, id := range ids {
+		// index of the first extent starting after 'id'
+		i, _ := slices.BinarySearchFunc(fa.free, id+1, func(run PageRun, id uint64) int {
+			return cmp.Compare(run.ID, id)
+		})
+
+		prev := i - 1
+		if prev >= 0 && id < fa.free[prev].ID+uint64(fa.free[prev].Len) {
+			return fmt.Errorf("%w (id=%d)", ErrDoubleFree, id)
+		}
+
+		joinsPrev := prev >= 0 && fa.free[prev].ID+uint64(fa.free[prev].Len) == id
+		joinsNext := i < len(fa.free) && fa.free[i].ID == id+1
+		switch {
+		case joinsPrev && joinsNext:
+			fa.free[prev].Len += 1 + fa.free[i].Len
+			fa.free = slices.Delete(fa.free, i, i+1)
+		case joinsPrev:
+			fa.free[prev].Len++
+		case joinsNext:
+			fa.free[i].ID--
+			fa.free[i].Len++
+		default:
+			fa.free = slices.Insert(fa.free, i, PageRun{ID: id, Len: 1})
+		}
+		fa.count++
+	}
+	return nil
+}
+
+// FreeCount returns the number of free pages.
+func (fa *FreeListAllocator) FreeCount() int { return fa.count }
+
+func (fa *FreeListAllocator) FreeRuns() []PageRun { return slices.Clone(fa.free) }
+
+func (fa *FreeListAllocator) TrimTail() (int, error) {
+	last := len(fa.free) - 1
+	if last < 0 || fa.free[last].ID+uint64(fa.free[last].Len) != fa.space.Count() {
+		return 0, nil
+	}
+
+	n := fa.free[last].Len
+	if err := fa.space.Shrink(n); err != nil {
+		return 0, err
+	}
+	fa.free = fa.free[:last]
+	fa.count -= n
+	return n, nil
+}
+
+// BitmapAllocator tracks free pages with one bit per page and reuses them
+// for later allocations, the file is only grown when no sequential run of
+// free pages is long enough.
+//
+// The bitmap lives in memory only and is lost when the pager is closed, the
+// freed pages are then seen as allocated after reopening.
+type BitmapAllocator struct {
+	space PageSpace
+	bits  []uint64
+	free  int
+}
+
+// NewBitmapAllocator returns a BitmapAllocator for given page space.
+func NewBitmapAllocator(space PageSpace) Allocator {
+	return &BitmapAllocator{space: space}
+}
+
+func (ba *BitmapAllocator) Alloc(n int) ([]uint64, error) {
+	if ba.free >= n {
+		run := 0
+		for id := uint64(0); id < uint64(len(ba.bits))*64; id++ {
+			if !ba.isFree(id) {
+				run = 0
+				continue
+			}
+
+			if run++; run == n {
+				first := id + 1 - uint64(n)
+				for i := first; i <= id; i++ {
+					ba.set(i, false)
+				}
+				return sequence(first, n), nil
+			}
+		}
+	}
+
+	first, err := ba.space.Grow(n)
+	if err != nil {
+		return nil, err
+	}
+	return sequence(first, n), nil
+}
+
+func (ba *BitmapAllocator) Free(ids []uint64) error {
+	for _, id := range ids {
+		if ba.isFree(id) {
+			return fmt.Errorf("%w (id=%d)", ErrDoubleFree, id)
+		}
+		ba.set(id, true)
+	}
+	return nil
+}
+
+// FreeCount returns the number of free pages.
+func (ba *BitmapAllocator) FreeCount() int { return ba.free }
+
+func (ba *BitmapAllocator) FreeRuns() []PageRun {
+	runs := []PageRun{}
+	for id := uint64(0); id < uint64(len(ba.bits))*64; id++ {
+		if !ba.isFree(id) {
+			continue
+		}
+
+		if last := len(runs) - 1; last >= 0 && runs[last].ID+uint64(runs[last].Len) == id {
+			runs[last].Len++
+		} else {
+			runs = append(runs, PageRun{ID: id, Len: 1})
+		}
+	}
+	return runs
+}
+
+func (ba *BitmapAllocator) TrimTail() (int, error) {
+	n, count := 0, ba.space.Count()
+	for uint64(n) < count && ba.isFree(count-uint64(n)-1) {
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+
+	if err := ba.space.Shrink(n); err != nil {
+		return 0, err
+	}
+	for i := 1; i <= n; i++ {
+		ba.set(count-uint64(i), false)
+	}
+	return n, nil
+}
+
+func (ba *BitmapAllocator) isFree(id uint64) bool {
+	word := id / 64
+	return word < uint64(len(ba.bits)) && ba.bits[word]&(1<<(id%64)) != 0
+}
+
+func (ba *BitmapAllocator) set(id uint64, free bool) {
+	word := int(id / 64)
+	if word >= len(ba.bits) {
+		ba.bits = append(ba.bits, make([]uint64, word-len(ba.bits)+1)...)
+	}
+
+	before := bits.OnesCount64(ba.bits[word])
+	if free {
+		ba.bits[word] |= 1 << (id % 64)
+	} else {
+		ba.bits[word] &^= 1 << (id % 64)
+	}
+	ba.free += bits.OnesCount64(ba.bits[word]) - before
+}
+
+func sequence(first uint64, n int) []uint64 {
+	ids := make([]uint64, n)
+	for i := range ids {
+		ids[i] = first + uint64(i)
+	}
+	return ids
+}
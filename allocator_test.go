@@ -0,0 +1,126 @@
+package pager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateAllocator(t *testing.T) {
+	p := OpenNop(16)
+	defer p.Close()
+
+	_, err := p.Alloc(4)
+	require.NoError(t, err)
+
+	require.Error(t, p.FreePage(1))
+	require.NoError(t, p.FreePages([]uint64{3, 2}))
+	require.Equal(t, uint64(2), p.Count())
+
+	require.NoError(t, p.Free(0))
+	require.NoError(t, p.Free(-1))
+	require.Equal(t, uint64(2), p.Count())
+}
+
+func TestFreeListAllocator(t *testing.T) {
+	p := OpenNop(16, WithAllocator(NewFreeListAllocator))
+	defer p.Close()
+
+	_, err := p.Alloc(6)
+	require.NoError(t, err)
+
+	require.NoError(t, p.FreePages([]uint64{1, 3, 4}))
+	require.ErrorIs(t, p.FreePage(3), ErrDoubleFree)
+
+	id, err := p.Alloc(2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), id)
+
+	id, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), id)
+
+	// the free run at the end of the file is extended
+	require.NoError(t, p.FreePage(5))
+	id, err = p.Alloc(3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), id)
+	require.Equal(t, uint64(8), p.Count())
+	require.Equal(t, 0, p.allocator.(*FreeListAllocator).FreeCount())
+}
+
+func TestFreeListAllocator_Coalescing(t *testing.T) {
+	for _, order := range [][]uint64{
+		{2, 3, 4, 5},
+		{5, 4, 3, 2},
+		{2, 5, 3, 4},
+		{4, 2, 5, 3},
+	} {
+		p := OpenNop(16, WithAllocator(NewFreeListAllocator))
+
+		_, err := p.Alloc(8)
+		require.NoError(t, err)
+		for _, id := range order {
+			require.NoError(t, p.FreePage(id))
+		}
+
+		fa := p.allocator.(*FreeListAllocator)
+		require.Equal(t, []PageRun{{ID: 2, Len: 4}}, fa.FreeRuns(), "order %v", order)
+		require.Equal(t, 4, fa.FreeCount())
+		require.ErrorIs(t, p.FreePage(4), ErrDoubleFree)
+
+		id, err := p.Alloc(4)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), id)
+		require.Empty(t, fa.FreeRuns())
+	}
+}
+
+func TestBitmapAllocator(t *testing.T) {
+	p := OpenNop(16, WithAllocator(NewBitmapAllocator))
+	defer p.Close()
+
+	_, err := p.Alloc(100)
+	require.NoError(t, err)
+
+	require.NoError(t, p.FreePages([]uint64{10, 63, 64, 65}))
+	require.ErrorIs(t, p.FreePage(64), ErrDoubleFree)
+
+	id, err := p.Alloc(3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(63), id)
+
+	id, err = p.Alloc(2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), id)
+	require.Equal(t, 1, p.allocator.(*BitmapAllocator).FreeCount())
+
+	// nothing to trim, the file isn't truncated
+	truncates := p.SyscallStats().Truncate
+	require.NoError(t, p.TrimTail())
+	require.Equal(t, truncates, p.SyscallStats().Truncate)
+}
+
+func TestPager_AllocGranularity(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644,
+		WithAllocator(NewFreeListAllocator),
+		WithAllocGranularity(4),
+	)
+	require.NoError(t, err)
+	defer p.Close()
+
+	first, err := p.Alloc(3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), first)
+	_, err = p.Alloc(8)
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), p.Count())
+
+	require.NoError(t, p.FreePage(5))
+	require.Equal(t, []PageRun{{ID: 4, Len: 4}}, p.allocator.(FreeTracker).FreeRuns())
+
+	id, err := p.Alloc(2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), id)
+	require.Equal(t, uint64(12), p.Count())
+}
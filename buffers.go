@@ -0,0 +1,113 @@
+package pager
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultMaxPooledPages is the size, in pages, of the largest buffer kept by
+// the buffer pool unless changed by WithPreallocatedBuffers().
+const defaultMaxPooledPages = 256
+
+// BufferPoolStats describes the usage of the pager's buffer pool, see
+// GetBufferN.
+type BufferPoolStats struct {
+	// Gets is the number of buffers requested from the pool.
+	Gets int
+	// Hits is the number of requests served by a pooled buffer.
+	Hits int
+	// Puts is the number of buffers returned to the pool.
+	Puts int
+	// Dropped is the number of returned buffers not kept by the pool because
+	// they're above the size limit or weren't obtained from the pool.
+	Dropped int
+}
+
+// bufferPool keeps multi-page buffers in size classes of power of two pages,
+// up to 'maxPages' pages.
+type bufferPool struct {
+	pageSize int
+	maxPages int
+	classes  []sync.Pool
+
+	gets, hits, puts, dropped atomic.Int64
+}
+
+func newBufferPool(pageSize, maxPages int) *bufferPool {
+	if maxPages <= 0 {
+		maxPages = defaultMaxPooledPages
+	}
+	return &bufferPool{
+		pageSize: pageSize,
+		maxPages: maxPages,
+		classes:  make([]sync.Pool, sizeClass(maxPages)+1),
+	}
+}
+
+// sizeClass returns the index of the smallest size class holding 'pages'.
+func sizeClass(pages int) int {
+	return bits.Len(uint(pages - 1))
+}
+
+// fill adds 'n' new buffers of 'pages' pages to the pool.
+func (bp *bufferPool) fill(n, pages int) {
+	if pages <= 0 || pages > bp.maxPages {
+		return
+	}
+
+	class := sizeClass(pages)
+	for range n {
+		buf := make([]byte, (1<<class)*bp.pageSize)
+		bp.classes[class].Put(&buf)
+	}
+}
+
+func (bp *bufferPool) get(pages int) []byte {
+	bp.gets.Add(1)
+	if pages <= 0 {
+		return []byte{}
+	} else if pages > bp.maxPages {
+		return make([]byte, pages*bp.pageSize)
+	}
+
+	class := sizeClass(pages)
+	if buf, ok := bp.classes[class].Get().(*[]byte); ok {
+		bp.hits.Add(1)
+		return (*buf)[:pages*bp.pageSize]
+	}
+	return make([]byte, pages*bp.pageSize, (1<<class)*bp.pageSize)
+}
+
+func (bp *bufferPool) put(b []byte) {
+	pages := cap(b) / bp.pageSize
+	if pages == 0 || pages > bp.maxPages || cap(b)%bp.pageSize != 0 || pages&(pages-1) != 0 {
+		bp.dropped.Add(1)
+		return
+	}
+
+	bp.puts.Add(1)
+	b = b[:cap(b)]
+	bp.classes[sizeClass(pages)].Put(&b)
+}
+
+// GetBufferN returns a buffer of 'pages' physical pages, reusing a pooled
+// one when possible. The contents of the buffer are undefined, and it's
+// empty for non-positive 'pages'. The buffer can be handed back with
+// PutBuffer once it's no longer used.
+func (p *Pager) GetBufferN(pages int) []byte { return p.buffers.get(pages) }
+
+// PutBuffer returns a buffer obtained from GetBufferN to the pool. Buffers
+// larger than the limit of the pool (see WithPreallocatedBuffers) are left
+// to the garbage collector.
+func (p *Pager) PutBuffer(b []byte) { p.buffers.put(b) }
+
+// BufferPoolStats returns the usage counters of the buffer pool.
+func (p *Pager) BufferPoolStats() BufferPoolStats {
+	return BufferPoolStats{
+		Gets:    int(p.buffers.gets.Load()),
+		Hits:    int(p.buffers.hits.Load()),
+		Puts:    int(p.buffers.puts.Load()),
+		Dropped: int(p.buffers.dropped.Load()),
+	}
+}
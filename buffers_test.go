@@ -0,0 +1,45 @@
+package pager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_GetBufferN(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644, WithPreallocatedBuffers(0, 4))
+	require.NoError(t, err)
+	defer p.Close()
+
+	buf := p.GetBufferN(3)
+	require.Len(t, buf, 3*16)
+	require.Equal(t, 4*16, cap(buf))
+	p.PutBuffer(buf)
+
+	buf = p.GetBufferN(4)
+	require.Len(t, buf, 4*16)
+	p.PutBuffer(buf)
+
+	large := p.GetBufferN(5)
+	require.Len(t, large, 5*16)
+	p.PutBuffer(large)
+	p.PutBuffer(make([]byte, 10))
+
+	stats := p.BufferPoolStats()
+	require.Equal(t, 3, stats.Gets)
+	require.Equal(t, 2, stats.Puts)
+	require.Equal(t, 2, stats.Dropped)
+	require.LessOrEqual(t, stats.Hits, 1)
+}
+
+func TestPager_GetBufferN_NonPositive(t *testing.T) {
+	p := OpenNop(16)
+	defer p.Close()
+
+	for _, pages := range []int{0, -1} {
+		buf := p.GetBufferN(pages)
+		require.Empty(t, buf)
+		p.PutBuffer(buf)
+	}
+	require.Equal(t, 2, p.BufferPoolStats().Dropped)
+}
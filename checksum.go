@@ -0,0 +1,71 @@
+package pager
+
+import (
+	"hash/crc64"
+	"hash/fnv"
+	"os"
+)
+
+// crcTable is the table used by Checksum.
+var crcTable = crc64.MakeTable(crc64.ECMA)
+
+// Checksum returns the CRC-64 (ECMA polynomial) of the raw contents of all
+// allocated pages, including page trailers. The whole file is read with the
+// pager locked shared, so writers wait until it's done. The digest isn't
+// stored anywhere, comparing it with a previously computed one is up to the
+// caller.
+func (p *Pager) Checksum() (uint64, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var sum uint64
+	err := p.scanPages(func(_ uint64, page []byte) {
+		sum = crc64.Update(sum, crcTable, page)
+	})
+	return sum, err
+}
+
+// StateHash returns the 64-bit FNV-1a hash of the data of all allocated
+// pages in id order. Unlike Checksum, page trailers and alignment padding
+// are left out, so pagers with the same page contents get the same hash
+// regardless of their backend and options. The pages are read in chunks
+// with the pager locked shared.
+func (p *Pager) StateHash() (uint64, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	size := p.usableSize()
+	h := fnv.New64a()
+	err := p.scanPages(func(_ uint64, page []byte) {
+		h.Write(page[:size])
+	})
+	return h.Sum64(), err
+}
+
+// scanPages calls 'fn' with the raw contents of every allocated page in id
+// order, reading the pages in chunks (see WithStreamBufferSize). The page
+// slices are only valid during the call.
+func (p *Pager) scanPages(fn func(id uint64, page []byte)) error {
+	if p.file == nil {
+		return os.ErrClosed
+	}
+
+	chunkPages := p.streamPages()
+	buf := p.buffers.get(chunkPages)
+	defer p.buffers.put(buf)
+
+	for id := uint64(0); id < p.count; {
+		k := min(uint64(chunkPages), p.count-id)
+		chunk := buf[:k*uint64(p.pageSize)]
+		if err := p.readPages(chunk, id); err != nil {
+			return err
+		}
+
+		for i := uint64(0); i < k; i++ {
+			off := int(i) * p.pageSize
+			fn(id+i, chunk[off:off+p.pageSize])
+		}
+		id += k
+	}
+	return nil
+}
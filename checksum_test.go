@@ -0,0 +1,59 @@
+package pager
+
+import (
+	"hash/crc64"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_Checksum(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(40)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(33, []byte{1}))
+
+	data, err := p.ReadAll()
+	require.NoError(t, err)
+
+	sum, err := p.Checksum()
+	require.NoError(t, err)
+	require.Equal(t, crc64.Checksum(data, crc64.MakeTable(crc64.ECMA)), sum)
+
+	require.NoError(t, p.Write(33, []byte{2}))
+	changed, err := p.Checksum()
+	require.NoError(t, err)
+	require.NotEqual(t, sum, changed)
+}
+
+func TestPager_StateHash(t *testing.T) {
+	mem, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer mem.Close()
+
+	versioned, err := Open(filepath.Join(t.TempDir(), "pager.bin"), 24, 0644, WithPageVersions())
+	require.NoError(t, err)
+	defer versioned.Close()
+
+	for _, p := range []*Pager{mem, versioned} {
+		_, err := p.Alloc(3)
+		require.NoError(t, err)
+		require.NoError(t, p.Write(2, []byte("state")))
+	}
+	require.NoError(t, versioned.WriteVersioned(0, nil, 7))
+
+	memHash, err := mem.StateHash()
+	require.NoError(t, err)
+	versionedHash, err := versioned.StateHash()
+	require.NoError(t, err)
+	require.Equal(t, memHash, versionedHash)
+
+	require.NoError(t, mem.Write(1, []byte{1}))
+	changed, err := mem.StateHash()
+	require.NoError(t, err)
+	require.NotEqual(t, memHash, changed)
+}
@@ -0,0 +1,137 @@
+package pager
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrNotResumable is returned by CopyFrom when the pager holds pages that
+// are not the beginning of an earlier copy of the source.
+var ErrNotResumable = errors.New("pages are not a prefix of the source")
+
+// CopyFrom copies the data of all pages of 'src' into this pager, allocating
+// pages as needed. The data of the source pages is treated as one byte stream
+// and re-chunked when the page sizes differ, the last page is padded with
+// zeros. 'progress', if not nil, is called after each written page with the
+// number of copied and total bytes of the stream.
+//
+// The copy resumes where it stopped when the pager already holds pages from
+// an interrupted CopyFrom: the last existing page is re-copied and the copy
+// continues after it. The other existing pages are compared with the source
+// first and the copy fails with ErrNotResumable, leaving the pager
+// untouched, if they differ. This pager is locked for the whole duration of
+// the copy.
+func (p *Pager) CopyFrom(src *Pager, progress func(copied, total int64)) error {
+	if src == p {
+		return errors.New("can't copy a pager into itself")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	} else if p.readOnly {
+		return ErrReadOnly
+	}
+
+	srcSize, dstSize := int64(src.PageSize()), int64(p.usableSize())
+	total := int64(src.Count()) * srcSize
+
+	start := p.count
+	if start > 0 {
+		start--
+	}
+	copied := int64(start) * dstSize
+	if err := p.checkCopied(src, start, total); err != nil {
+		return err
+	}
+
+	r := &pageReader{
+		src:  src,
+		id:   uint64(copied / srcSize),
+		skip: int(copied % srcSize),
+	}
+
+	buf := make([]byte, dstSize)
+	for id := start; copied < total; id++ {
+		clear(buf)
+		n, err := io.ReadFull(r, buf)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+
+		if id >= p.count {
+			if _, err := p.grow(1); err != nil {
+				return err
+			}
+			p.allocs.Add(1)
+		}
+		if err := p.writePage(id, buf); err != nil {
+			return err
+		}
+
+		copied += int64(n)
+		if progress != nil {
+			progress(copied, total)
+		}
+	}
+	return nil
+}
+
+// checkCopied checks that the first 'n' pages of this pager hold the
+// beginning of the data of 'src', whose data is 'total' bytes long.
+func (p *Pager) checkCopied(src *Pager, n uint64, total int64) error {
+	if n == 0 {
+		return nil
+	} else if int64(n)*int64(p.usableSize()) >= total {
+		return fmt.Errorf("%w (pages=%d, source bytes=%d)", ErrNotResumable, n+1, total)
+	}
+
+	r := &pageReader{src: src}
+	buf := make([]byte, p.usableSize())
+	for id := uint64(0); id < n; id++ {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+
+		data, err := p.readPage(id)
+		if err != nil {
+			return err
+		} else if !bytes.Equal(data, buf) {
+			return fmt.Errorf("%w (page %d differs)", ErrNotResumable, id)
+		}
+	}
+	return nil
+}
+
+// pageReader reads the data of pages of a pager as a byte stream.
+type pageReader struct {
+	src  *Pager
+	id   uint64
+	skip int
+	buf  []byte
+}
+
+func (r *pageReader) Read(b []byte) (int, error) {
+	if len(r.buf) == 0 {
+		if r.id >= r.src.Count() {
+			return 0, io.EOF
+		}
+
+		data, err := r.src.Read(r.id)
+		if err != nil {
+			return 0, err
+		}
+		r.buf = data[r.skip:]
+		r.id++
+		r.skip = 0
+	}
+
+	n := copy(b, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
@@ -0,0 +1,81 @@
+package pager
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_CopyFrom(t *testing.T) {
+	src, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer src.Close()
+
+	_, err = src.Alloc(5)
+	require.NoError(t, err)
+	for i := uint64(0); i < 5; i++ {
+		require.NoError(t, src.Write(i, bytes.Repeat([]byte{byte(i + 1)}, 16)))
+	}
+
+	dst, err := Open(InMemoryFileName, 32, 0644)
+	require.NoError(t, err)
+	defer dst.Close()
+
+	calls := 0
+	require.NoError(t, dst.CopyFrom(src, func(copied, total int64) {
+		calls++
+		require.Equal(t, int64(80), total)
+	}))
+	require.Equal(t, 3, calls)
+	require.Equal(t, uint64(3), dst.Count())
+
+	data, err := dst.Read(2)
+	require.NoError(t, err)
+	require.Equal(t, append(bytes.Repeat([]byte{5}, 16), make([]byte, 16)...), data)
+
+	// resuming an interrupted copy re-copies the last page only
+	require.NoError(t, dst.Free(1))
+	require.NoError(t, dst.ZeroPage(1))
+	require.NoError(t, dst.CopyFrom(src, nil))
+	require.Equal(t, uint64(3), dst.Count())
+
+	data, err = dst.Read(1)
+	require.NoError(t, err)
+	require.Equal(t, append(bytes.Repeat([]byte{3}, 16), bytes.Repeat([]byte{4}, 16)...), data)
+}
+
+func TestPager_CopyFrom_NotResumable(t *testing.T) {
+	src, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer src.Close()
+
+	_, err = src.Alloc(4)
+	require.NoError(t, err)
+	for i := uint64(0); i < 4; i++ {
+		require.NoError(t, src.Write(i, bytes.Repeat([]byte{byte(i + 1)}, 16)))
+	}
+
+	dst, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer dst.Close()
+
+	_, err = dst.Alloc(3)
+	require.NoError(t, err)
+	require.NoError(t, dst.Write(0, []byte("unrelated")))
+	require.ErrorIs(t, dst.CopyFrom(src, nil), ErrNotResumable)
+
+	data, err := dst.Read(0)
+	require.NoError(t, err)
+	require.Equal(t, []byte("unrelated"), data[:9])
+
+	// more pages than the source data fills
+	big, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer big.Close()
+
+	_, err = big.Alloc(6)
+	require.NoError(t, err)
+	require.ErrorIs(t, big.CopyFrom(src, nil), ErrNotResumable)
+	require.Equal(t, uint64(6), big.Count())
+}
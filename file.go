@@ -4,10 +4,12 @@ import (
 	"errors"
 	"io"
 	"os"
+	"time"
 )
 
 var (
 	_ RandomAccessFile = (*inMemory)(nil)
+	_ RandomAccessFile = (*NopFile)(nil)
 	_ RandomAccessFile = (*os.File)(nil)
 )
 
@@ -22,6 +24,23 @@ type RandomAccessFile interface {
 	Name() string
 }
 
+// Deadliner can be implemented by a RandomAccessFile (e.g. a network-backed
+// one) to bound the duration of its I/O calls. SetDeadline is called with the
+// deadline set via Pager.SetDeadline before each ReadAt, WriteAt and Truncate
+// call, making them fail once the deadline is exceeded. A zero value means no
+// deadline. The error returned by SetDeadline is ignored, the following I/O
+// call is expected to report the failure.
+type Deadliner interface {
+	SetDeadline(t time.Time) error
+}
+
+// Sizer can be implemented by a RandomAccessFile to report its size, for
+// backends where neither stat nor seeking is meaningful (e.g. object stores).
+// When implemented, it takes precedence over os.File.Stat().
+type Sizer interface {
+	Size() (int64, error)
+}
+
 type sizedFile interface {
 	RandomAccessFile
 	Size() int64
@@ -81,6 +100,15 @@ func (mem *inMemory) Close() error {
 }
 
 func (mem *inMemory) Truncate(size int64) error {
+	if int(size) <= cap(mem.data) {
+		prevSize := len(mem.data)
+		mem.data = mem.data[:size]
+		if int(size) > prevSize {
+			clear(mem.data[prevSize:])
+		}
+		return nil
+	}
+
 	d := mem.data
 	mem.data = make([]byte, size)
 	copy(mem.data, d)
@@ -109,8 +137,32 @@ func (mem *inMemory) canMutate(op string) error {
 	return nil
 }
 
+// NopFile is a RandomAccessFile that doesn't store any data. Reads and writes
+// succeed without touching the buffers and Truncate only records the size.
+// It's useful to measure the overhead of the pager itself without any I/O.
+type NopFile struct {
+	size int64
+}
+
+func (nop *NopFile) ReadAt(p []byte, off int64) (int, error)  { return len(p), nil }
+func (nop *NopFile) WriteAt(p []byte, off int64) (int, error) { return len(p), nil }
+func (nop *NopFile) Close() error                             { return nil }
+func (nop *NopFile) Name() string                             { return "nop" }
+func (nop *NopFile) Size() int64                              { return nop.size }
+
+func (nop *NopFile) Truncate(size int64) error {
+	nop.size = size
+	return nil
+}
+
+// findSize determines the size of the file. The strategies are tried in the
+// following order: the Sizer interface, os.File.Stat() and finally the
+// internal sizedFile interface of the built-in backends.
 func findSize(f RandomAccessFile) (int64, error) {
 	switch file := f.(type) {
+	case Sizer:
+		return file.Size()
+
 	case *os.File:
 		stat, err := file.Stat()
 		if err != nil {
@@ -124,3 +176,8 @@ func findSize(f RandomAccessFile) (int64, error) {
 
 	return 0, errors.New("failed to find file size")
 }
+
+// readerAtFunc adapts a function to io.ReaderAt.
+type readerAtFunc func(p []byte, off int64) (int, error)
+
+func (fn readerAtFunc) ReadAt(p []byte, off int64) (int, error) { return fn(p, off) }
@@ -0,0 +1,83 @@
+package pager
+
+import (
+	"sync"
+	"time"
+)
+
+// FlushPolicy decides when the pager fsyncs the file on its own, see
+// WithFlushPolicy().
+type FlushPolicy int
+
+const (
+	// FlushManual never fsyncs automatically, data is only made durable by
+	// Sync and Barrier. It's the default.
+	FlushManual FlushPolicy = iota
+	// FlushOnWrite fsyncs after every write to the file, before the write
+	// returns. Unlike WithOSync() the file isn't opened with O_SYNC, so
+	// multiple writes done by a single call are flushed separately.
+	FlushOnWrite
+	// FlushInterval fsyncs periodically in a background goroutine and once
+	// more on Close.
+	FlushInterval
+	// FlushOnClose fsyncs when the pager is closed.
+	FlushOnClose
+)
+
+// flusher periodically fsyncs the file, see FlushInterval.
+type flusher struct {
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func (p *Pager) startFlusher(interval time.Duration) {
+	p.flusher = &flusher{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(p.flusher.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.flusher.stop:
+				return
+			case <-ticker.C:
+				if err := p.Sync(); err != nil {
+					p.debug("periodic flush failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+func (p *Pager) stopFlusher() {
+	if p.flusher == nil {
+		return
+	}
+
+	p.flusher.stopOnce.Do(func() { close(p.flusher.stop) })
+	<-p.flusher.done
+}
+
+// flushOnWrite fsyncs the file after a write if FlushOnWrite is set.
+func (p *Pager) flushOnWrite() error {
+	if p.opts.flushPolicy != FlushOnWrite {
+		return nil
+	}
+	return p.sync()
+}
+
+// flushOnClose fsyncs the file before it's closed if the flush policy asks
+// for it.
+func (p *Pager) flushOnClose() error {
+	if p.readOnly || (p.opts.flushPolicy != FlushInterval && p.opts.flushPolicy != FlushOnClose) {
+		return nil
+	}
+	return p.sync()
+}
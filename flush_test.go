@@ -0,0 +1,43 @@
+package pager
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_FlushPolicy(t *testing.T) {
+	dir := t.TempDir()
+	open := func(name string, opts ...Option) *Pager {
+		p, err := Open(filepath.Join(dir, name), 16, 0644, opts...)
+		require.NoError(t, err)
+		_, err = p.Alloc(2)
+		require.NoError(t, err)
+		return p
+	}
+
+	p := open("manual.bin")
+	require.NoError(t, p.Write(0, []byte{1}))
+	require.NoError(t, p.Close())
+	require.Equal(t, 0, p.Stats().Fsyncs)
+
+	p = open("write.bin", WithFlushPolicy(FlushOnWrite, 0))
+	require.NoError(t, p.Write(0, []byte{1}))
+	require.NoError(t, p.Write(1, []byte{2}))
+	require.Equal(t, 2, p.Stats().Fsyncs)
+	require.NoError(t, p.Close())
+	require.Equal(t, 2, p.Stats().Fsyncs)
+
+	p = open("close.bin", WithFlushPolicy(FlushOnClose, 0))
+	require.NoError(t, p.Write(0, []byte{1}))
+	require.Equal(t, 0, p.Stats().Fsyncs)
+	require.NoError(t, p.Close())
+	require.Equal(t, 1, p.Stats().Fsyncs)
+
+	p = open("interval.bin", WithFlushPolicy(FlushInterval, time.Millisecond))
+	require.NoError(t, p.Write(0, []byte{1}))
+	require.Eventually(t, func() bool { return p.Stats().Fsyncs > 0 }, time.Second, time.Millisecond)
+	require.NoError(t, p.Close())
+}
@@ -0,0 +1,97 @@
+package pager
+
+import "reflect"
+
+// HealthReport summarizes the state of a pager, see Pager.Health.
+type HealthReport struct {
+	Closed   bool
+	ReadOnly bool
+	PageSize int
+	Count    uint64
+
+	// FreeCount is the number of free pages, 0 if the allocator doesn't
+	// keep track of them.
+	FreeCount int
+
+	// Aligned tells whether the current size of the underlying file is a
+	// multiple of the page size.
+	Aligned bool
+}
+
+// Health returns a summary of the pager state. It's cheap enough to be
+// called frequently: the only I/O is finding the current size of the file.
+// The pager has no page checksums, so no corruption probing is done.
+func (p *Pager) Health() (HealthReport, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	report := HealthReport{
+		Closed:   p.file == nil,
+		ReadOnly: p.readOnly,
+		PageSize: p.usableSize(),
+		Count:    p.count,
+	}
+	if report.Closed {
+		return report, nil
+	}
+
+	if counter, ok := p.allocator.(interface{ FreeCount() int }); ok {
+		report.FreeCount = counter.FreeCount()
+	}
+
+	size, err := findSize(p.file)
+	if err != nil {
+		return report, err
+	}
+	report.Aligned = size%int64(p.pageSize) == 0
+
+	return report, nil
+}
+
+// Fragmentation returns the fraction of allocated pages that are free and
+// scattered in the file: the number of free pages, not counting the run of
+// free pages at the end of the file (which TrimTail removes without any
+// compaction), divided by the page count. It's 0 for an empty pager and for
+// allocators that don't implement FreeTracker. Nothing is modified, so it's
+// safe to call in read-only mode.
+func (p *Pager) Fragmentation() float64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	tracker, ok := p.allocator.(FreeTracker)
+	if !ok || p.count == 0 {
+		return 0
+	}
+
+	var free uint64
+	for _, run := range tracker.FreeRuns() {
+		if run.ID+uint64(run.Len) < p.count {
+			free += uint64(run.Len)
+		}
+	}
+	return float64(free) / float64(p.count)
+}
+
+// HasFreeList reports whether the configured allocator keeps track of freed
+// pages for reuse (see FreeTracker). Without a free list only the pages at
+// the end of the file can be freed, by truncating it.
+func (p *Pager) HasFreeList() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	_, ok := p.allocator.(FreeTracker)
+	return ok
+}
+
+// AllocatorType returns the name of the type of the configured allocator,
+// e.g. "TruncateAllocator".
+func (p *Pager) AllocatorType() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	t := reflect.TypeOf(p.allocator)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
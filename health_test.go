@@ -0,0 +1,47 @@
+package pager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_Health(t *testing.T) {
+	p := OpenNop(16, WithAllocator(NewFreeListAllocator))
+
+	_, err := p.Alloc(3)
+	require.NoError(t, err)
+	require.NoError(t, p.FreePage(1))
+
+	report, err := p.Health()
+	require.NoError(t, err)
+	require.Equal(t, HealthReport{PageSize: 16, Count: 3, FreeCount: 1, Aligned: true}, report)
+
+	require.NoError(t, p.Close())
+	report, err = p.Health()
+	require.NoError(t, err)
+	require.True(t, report.Closed)
+}
+
+func TestPager_Fragmentation(t *testing.T) {
+	p := OpenNop(16, WithAllocator(NewBitmapAllocator))
+	defer p.Close()
+	require.Zero(t, p.Fragmentation())
+
+	_, err := p.Alloc(8)
+	require.NoError(t, err)
+	require.NoError(t, p.FreePages([]uint64{1, 3, 6, 7}))
+	require.Equal(t, 0.25, p.Fragmentation())
+
+	require.Zero(t, OpenNop(16).Fragmentation())
+}
+
+func TestPager_AllocatorType(t *testing.T) {
+	p := OpenNop(16)
+	require.False(t, p.HasFreeList())
+	require.Equal(t, "TruncateAllocator", p.AllocatorType())
+
+	p = OpenNop(16, WithAllocator(NewFreeListAllocator))
+	require.True(t, p.HasFreeList())
+	require.Equal(t, "FreeListAllocator", p.AllocatorType())
+}
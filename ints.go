@@ -0,0 +1,144 @@
+package pager
+
+import (
+	"fmt"
+	"os"
+)
+
+// PutUint64 stores 'v' at offset 'off' within the page with given id, using
+// the big-endian byte order of the pager. Only the bytes of the value are
+// written, the rest of the page is left untouched.
+func (p *Pager) PutUint64(id uint64, off int, v uint64) error {
+	b := make([]byte, 8)
+	bin.PutUint64(b, v)
+	return p.putBytes(id, off, b)
+}
+
+// GetUint64 returns the value stored by PutUint64.
+func (p *Pager) GetUint64(id uint64, off int) (uint64, error) {
+	b, err := p.getBytes(id, off, 8)
+	if err != nil {
+		return 0, err
+	}
+	return bin.Uint64(b), nil
+}
+
+// PutUint32 is like PutUint64 for 32-bit values.
+func (p *Pager) PutUint32(id uint64, off int, v uint32) error {
+	b := make([]byte, 4)
+	bin.PutUint32(b, v)
+	return p.putBytes(id, off, b)
+}
+
+// GetUint32 returns the value stored by PutUint32.
+func (p *Pager) GetUint32(id uint64, off int) (uint32, error) {
+	b, err := p.getBytes(id, off, 4)
+	if err != nil {
+		return 0, err
+	}
+	return bin.Uint32(b), nil
+}
+
+// PutUint16 is like PutUint64 for 16-bit values.
+func (p *Pager) PutUint16(id uint64, off int, v uint16) error {
+	b := make([]byte, 2)
+	bin.PutUint16(b, v)
+	return p.putBytes(id, off, b)
+}
+
+// GetUint16 returns the value stored by PutUint16.
+func (p *Pager) GetUint16(id uint64, off int) (uint16, error) {
+	b, err := p.getBytes(id, off, 2)
+	if err != nil {
+		return 0, err
+	}
+	return bin.Uint16(b), nil
+}
+
+// IncrementUint64 atomically adds 'delta' to the value stored by PutUint64
+// at offset 'off' within the page with given id and returns the new value.
+// The pager is locked exclusively for the whole read-modify-write, so
+// concurrent increments don't lose updates. The value wraps around on
+// overflow.
+func (p *Pager) IncrementUint64(id uint64, off int, delta uint64) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.checkPageRange(id, off, 8); err != nil {
+		return 0, err
+	} else if p.readOnly {
+		return 0, ErrReadOnly
+	}
+
+	b, err := p.readInPage(id, off, 8)
+	if err != nil {
+		return 0, err
+	}
+
+	v := bin.Uint64(b) + delta
+	bin.PutUint64(b, v)
+	if err := p.writeInPage(id, off, b); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+func (p *Pager) putBytes(id uint64, off int, b []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.checkPageRange(id, off, len(b)); err != nil {
+		return err
+	} else if p.readOnly {
+		return ErrReadOnly
+	}
+	return p.writeInPage(id, off, b)
+}
+
+func (p *Pager) getBytes(id uint64, off, size int) ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if err := p.checkPageRange(id, off, size); err != nil {
+		return nil, err
+	}
+	return p.readInPage(id, off, size)
+}
+
+// checkPageRange checks that 'size' bytes at offset 'off' are within the
+// data of the page with given id.
+func (p *Pager) checkPageRange(id uint64, off, size int) error {
+	if id >= p.count {
+		return fmt.Errorf("invalid page id=%d (max=%d)", id, p.count-1)
+	} else if off < 0 || off+size > p.usableSize() {
+		return fmt.Errorf("invalid page offset=%d (size=%d, pageSize=%d)", off, size, p.usableSize())
+	} else if p.file == nil {
+		return os.ErrClosed
+	}
+	return nil
+}
+
+func (p *Pager) readInPage(id uint64, off, size int) ([]byte, error) {
+	b := make([]byte, size)
+	start := p.offset(id) + int64(off)
+
+	if !p.copyMapped(b, start) {
+		if n, err := p.readAt(b, start); n < size {
+			return nil, shortRead(size, n, err)
+		}
+	}
+
+	p.reads.Add(1)
+	p.trackAccess(id, false)
+	return b, nil
+}
+
+func (p *Pager) writeInPage(id uint64, off int, b []byte) error {
+	if _, err := p.writeAt(b, p.offset(id)+int64(off)); err != nil {
+		return err
+	}
+
+	p.writes.Add(1)
+	p.trackAccess(id, true)
+	return nil
+}
@@ -0,0 +1,71 @@
+package pager
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_Ints(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	id, err := p.Alloc(1)
+	require.NoError(t, err)
+
+	require.NoError(t, p.PutUint64(id, 8, 1<<40))
+	require.NoError(t, p.PutUint32(id, 4, 1<<20))
+	require.NoError(t, p.PutUint16(id, 0, 1<<10))
+
+	v64, err := p.GetUint64(id, 8)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1<<40), v64)
+
+	v32, err := p.GetUint32(id, 4)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1<<20), v32)
+
+	v16, err := p.GetUint16(id, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint16(1<<10), v16)
+
+	data, err := p.Read(id)
+	require.NoError(t, err)
+	require.Equal(t, []byte{4, 0, 0, 0, 0, 16, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0}, data)
+
+	require.Error(t, p.PutUint64(id, 9, 1))
+	require.Error(t, p.PutUint16(id, -1, 1))
+	_, err = p.GetUint32(1, 0)
+	require.Error(t, err)
+}
+
+func TestPager_IncrementUint64(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	id, err := p.Alloc(1)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				_, err := p.IncrementUint64(id, 8, 2)
+				require.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	v, err := p.IncrementUint64(id, 8, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(201), v)
+
+	_, err = p.IncrementUint64(id, 9, 1)
+	require.Error(t, err)
+}
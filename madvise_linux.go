@@ -0,0 +1,17 @@
+//go:build linux
+
+package pager
+
+import "syscall"
+
+// madvise passes the access pattern of the mapping to the kernel.
+func madvise(b []byte, advice MmapAdvice) error {
+	flag := syscall.MADV_NORMAL
+	switch advice {
+	case MmapAdviceRandom:
+		flag = syscall.MADV_RANDOM
+	case MmapAdviceSequential:
+		flag = syscall.MADV_SEQUENTIAL
+	}
+	return syscall.Madvise(b, flag)
+}
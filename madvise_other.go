@@ -0,0 +1,8 @@
+//go:build !linux
+
+package pager
+
+// madvise is not available on this platform, the advice is ignored.
+func madvise(b []byte, advice MmapAdvice) error {
+	return nil
+}
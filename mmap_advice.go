@@ -0,0 +1,25 @@
+package pager
+
+// MmapAdvice is the expected access pattern of memory mapped files passed to
+// the kernel with madvise, see WithMmapAdvice().
+type MmapAdvice int
+
+const (
+	// MmapAdviceNormal keeps the default readahead of the kernel.
+	MmapAdviceNormal MmapAdvice = iota
+	// MmapAdviceRandom expects random access, disabling readahead.
+	MmapAdviceRandom
+	// MmapAdviceSequential expects sequential scans, enabling aggressive
+	// readahead.
+	MmapAdviceSequential
+)
+
+// advise applies 'advice' to a new mapping. Fresh mappings already use the
+// normal access pattern, so nothing is done for MmapAdviceNormal. It's only a
+// hint, failures are ignored by the callers.
+func advise(data []byte, advice MmapAdvice) error {
+	if advice == MmapAdviceNormal || len(data) == 0 {
+		return nil
+	}
+	return madvise(data, advice)
+}
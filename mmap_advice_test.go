@@ -0,0 +1,43 @@
+//go:build unix
+
+package pager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_MmapAdvice(t *testing.T) {
+	dir := t.TempDir()
+	for name, opts := range map[string][]Option{
+		"random.bin":     {WithReadMmap(), WithMmapAdvice(MmapAdviceRandom)},
+		"sequential.bin": {WithReadMmap(), WithMmapAdvice(MmapAdviceSequential), WithMmapWindow(1)},
+	} {
+		p, err := Open(filepath.Join(dir, name), 16, 0644, opts...)
+		require.NoError(t, err)
+		defer p.Close()
+
+		_, err = p.Alloc(4)
+		require.NoError(t, err)
+		require.NoError(t, p.Write(3, []byte{3}))
+
+		data, err := p.Read(3)
+		require.NoError(t, err)
+		require.Equal(t, byte(3), data[0])
+		require.NotZero(t, p.Stats().MmapRemaps+p.Stats().CacheMisses, name)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "random.bin"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	data, err := mmap(f, 0, 16, false)
+	require.NoError(t, err)
+	defer munmap(data)
+	for _, advice := range []MmapAdvice{MmapAdviceNormal, MmapAdviceRandom, MmapAdviceSequential} {
+		require.NoError(t, advise(data, advice))
+	}
+}
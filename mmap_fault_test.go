@@ -0,0 +1,35 @@
+//go:build linux
+
+package pager
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_MmapFaultRecovery(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "pager.bin")
+	p, err := Open(fileName, os.Getpagesize(), 0644, WithReadMmap(), WithMmapFaultRecovery())
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(2)
+	require.NoError(t, err)
+	require.NotNil(t, p.mmap)
+
+	// truncate the file behind the pager's back
+	require.NoError(t, os.Truncate(fileName, 0))
+
+	_, err = p.Read(1)
+	require.ErrorIs(t, err, io.EOF)
+
+	// other panics are not mistaken for faults
+	mapped := p.mmap
+	p.mmap = mapped[:16]
+	defer func() { p.mmap = mapped }()
+	require.Panics(t, func() { p.copyMapped(make([]byte, 8), 32) })
+}
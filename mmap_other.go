@@ -0,0 +1,14 @@
+//go:build !unix
+
+package pager
+
+import "os"
+
+// mmap is not available on this platform.
+func mmap(f *os.File, off, size int64, writable bool) ([]byte, error) {
+	return nil, errNoMmap
+}
+
+func munmap(b []byte) error {
+	return nil
+}
@@ -0,0 +1,23 @@
+//go:build unix
+
+package pager
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmap maps 'size' bytes of the file starting from 'off' into memory for
+// reading, and for writing as well if 'writable' is set. The offset must be
+// a multiple of the OS page size.
+func mmap(f *os.File, off, size int64, writable bool) ([]byte, error) {
+	prot := syscall.PROT_READ
+	if writable {
+		prot |= syscall.PROT_WRITE
+	}
+	return syscall.Mmap(int(f.Fd()), off, int(size), prot, syscall.MAP_SHARED)
+}
+
+func munmap(b []byte) error {
+	return syscall.Munmap(b)
+}
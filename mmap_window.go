@@ -0,0 +1,149 @@
+package pager
+
+import (
+	"container/list"
+	"os"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// maxMmapWindows is the number of windows kept mapped by WithMmapWindow().
+const maxMmapWindows = 8
+
+// mmapWindows maps a file into memory in fixed-size windows, keeping the
+// most recently used ones mapped.
+type mmapWindows struct {
+	// mu guards the windows, reads from the windows hold it since a window
+	// can be unmapped by any other read.
+	mu sync.Mutex
+
+	file     *os.File
+	size     int64 // size of the file
+	winBytes int64 // size of one window
+	advice   MmapAdvice
+
+	lru    *list.List // of *mmapWindow, most recently used first
+	byIdx  map[int64]*list.Element
+	failed bool // mmap is not available
+
+	hits, misses, evictions atomic.Int64
+}
+
+type mmapWindow struct {
+	idx  int64
+	data []byte
+}
+
+func newMmapWindows(f *os.File, winBytes int) *mmapWindows {
+	osPage := os.Getpagesize()
+	if rem := winBytes % osPage; rem != 0 {
+		winBytes += osPage - rem
+	}
+
+	return &mmapWindows{
+		file:     f,
+		winBytes: int64(winBytes),
+		lru:      list.New(),
+		byIdx:    map[int64]*list.Element{},
+	}
+}
+
+// reset unmaps all the windows and sets the size of the file to map.
+func (w *mmapWindows) reset(size int64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var err error
+	for e := w.lru.Front(); e != nil; e = e.Next() {
+		if unmapErr := munmap(e.Value.(*mmapWindow).data); err == nil {
+			err = unmapErr
+		}
+	}
+	w.lru.Init()
+	clear(w.byIdx)
+	w.size = size
+	return err
+}
+
+// copy copies the data at offset 'off' into 'dst', mapping the windows it
+// spans as needed. It returns false if the data can't be served from the
+// mapping, in which case 'dst' must be read from the file.
+func (w *mmapWindows) copy(dst []byte, off int64) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failed || off+int64(len(dst)) > w.size {
+		return false
+	}
+
+	for len(dst) > 0 {
+		win := w.window(off / w.winBytes)
+		if win == nil {
+			return false
+		}
+
+		n := copy(dst, win.data[off-win.idx*w.winBytes:])
+		dst, off = dst[n:], off+int64(n)
+	}
+	return true
+}
+
+// window returns the mapped window with given index, mapping it and
+// unmapping the least recently used one if needed.
+func (w *mmapWindows) window(idx int64) *mmapWindow {
+	if e, ok := w.byIdx[idx]; ok {
+		w.hits.Add(1)
+		w.lru.MoveToFront(e)
+		return e.Value.(*mmapWindow)
+	}
+	w.misses.Add(1)
+
+	start := idx * w.winBytes
+	data, err := mmap(w.file, start, min(w.winBytes, w.size-start), false)
+	if err != nil {
+		w.failed = true
+		return nil
+	}
+	advise(data, w.advice)
+
+	if w.lru.Len() >= maxMmapWindows {
+		oldest := w.lru.Remove(w.lru.Back()).(*mmapWindow)
+		delete(w.byIdx, oldest.idx)
+		munmap(oldest.data)
+		w.evictions.Add(1)
+	}
+
+	win := &mmapWindow{idx: idx, data: data}
+	w.byIdx[idx] = w.lru.PushFront(win)
+	return win
+}
+
+// copyMapped copies the data at offset 'off' of the file into 'dst' from
+// the memory mapping. It returns false if the file isn't mapped, or if the
+// access faulted and WithMmapFaultRecovery() is set. Panics other than
+// faults are not recovered.
+func (p *Pager) copyMapped(dst []byte, off int64) (ok bool) {
+	if p.opts.mmapFaultRecovery && (p.windows != nil || p.mmap != nil) {
+		defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+		defer func() {
+			if r := recover(); r != nil {
+				// faults raised under SetPanicOnFault carry the faulting address
+				if _, fault := r.(interface{ Addr() uintptr }); !fault {
+					panic(r)
+				}
+				p.debug("mmap access faulted, falling back to ReadAt", "err", r)
+				ok = false
+			}
+		}()
+	}
+
+	if p.windows != nil {
+		return p.windows.copy(dst, off)
+	} else if p.mmap == nil {
+		return false
+	}
+
+	copy(dst, p.mmap[off:])
+	return true
+}
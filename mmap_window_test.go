@@ -0,0 +1,47 @@
+//go:build unix
+
+package pager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_MmapWindow(t *testing.T) {
+	pageSize := os.Getpagesize()
+	p, err := Open(filepath.Join(t.TempDir(), "pager.bin"), pageSize, 0644,
+		WithReadMmap(),
+		WithMmapWindow(1),
+	)
+	require.NoError(t, err)
+	defer p.Close()
+
+	n := maxMmapWindows + 4
+	_, err = p.Alloc(n)
+	require.NoError(t, err)
+	for id := range n {
+		require.NoError(t, p.Write(uint64(id), []byte{byte(id + 1)}))
+	}
+
+	for id := range n {
+		data, err := p.Read(uint64(id))
+		require.NoError(t, err)
+		require.Equal(t, byte(id+1), data[0])
+	}
+	require.Nil(t, p.mmap)
+	require.False(t, p.windows.failed)
+	require.Equal(t, maxMmapWindows, p.windows.lru.Len())
+
+	stats := p.Stats()
+	require.Equal(t, n, stats.CacheMisses)
+	require.Equal(t, n-maxMmapWindows, stats.Evictions)
+	require.Zero(t, stats.CacheHits)
+	require.Zero(t, stats.MmapRemaps)
+
+	buf := make([]byte, 2)
+	require.NoError(t, p.ReadAt(buf, uint64(pageSize-1)))
+	require.Equal(t, []byte{0, 2}, buf)
+}
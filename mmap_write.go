@@ -0,0 +1,66 @@
+package pager
+
+import "os"
+
+// msyncDirty flushes the dirty pages of a writable mapping to the file with
+// msync(MS_SYNC). The file must still be fsynced afterwards to make its
+// metadata durable.
+func (p *Pager) msyncDirty() error {
+	if p.mmap == nil {
+		return nil
+	}
+
+	osPage := int64(os.Getpagesize())
+	for _, run := range p.dirty.runs() {
+		start := p.offset(run.ID)
+		end := min(p.offset(run.ID+uint64(run.Len)), int64(len(p.mmap)))
+		if start >= end {
+			continue
+		}
+
+		start -= start % osPage
+		if err := msync(p.mmap[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pageBitmap is a set of page ids with one bit per page, used to track the
+// pages written through a writable mapping (see WithWriteMmap).
+type pageBitmap []uint64
+
+// markRange adds the pages from 'first' to 'last' inclusive to the set.
+func (b *pageBitmap) markRange(first, last uint64) {
+	if word := int(last / 64); word >= len(*b) {
+		*b = append(*b, make([]uint64, word-len(*b)+1)...)
+	}
+	for id := first; id <= last; id++ {
+		(*b)[id/64] |= 1 << (id % 64)
+	}
+}
+
+func (b pageBitmap) has(id uint64) bool {
+	word := id / 64
+	return word < uint64(len(b)) && b[word]&(1<<(id%64)) != 0
+}
+
+// runs returns the sequences of pages in the set.
+func (b pageBitmap) runs() []PageRun {
+	runs := []PageRun{}
+	for id := uint64(0); id < uint64(len(b))*64; id++ {
+		if !b.has(id) {
+			continue
+		}
+
+		if last := len(runs) - 1; last >= 0 && runs[last].ID+uint64(runs[last].Len) == id {
+			runs[last].Len++
+		} else {
+			runs = append(runs, PageRun{ID: id, Len: 1})
+		}
+	}
+	return runs
+}
+
+// reset empties the set.
+func (b *pageBitmap) reset() { *b = nil }
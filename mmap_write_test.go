@@ -0,0 +1,67 @@
+//go:build unix
+
+package pager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_WriteMmap(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "pager.bin")
+	p, err := Open(fileName, 16, 0644, WithWriteMmap())
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(4)
+	require.NoError(t, err)
+	require.NotNil(t, p.mmap)
+
+	require.NoError(t, p.Write(1, []byte{1, 2}))
+	require.NoError(t, p.PutUint16(3, 0, 0x0304))
+	require.Equal(t, pageBitmap{0b1010}, p.dirty)
+
+	data, err := p.Read(1)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, data[:2])
+
+	require.NoError(t, p.Sync())
+	require.Empty(t, p.dirty)
+
+	raw, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2}, raw[16:18])
+	require.Equal(t, []byte{3, 4}, raw[48:50])
+}
+
+func TestPager_WriteMmapSync(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "pager.bin")
+	p, err := Open(fileName, 16, 0644, WithWriteMmap())
+	require.NoError(t, err)
+
+	_, err = p.Alloc(300)
+	require.NoError(t, err)
+	for _, id := range []uint64{0, 1, 2, 299} {
+		require.NoError(t, p.Write(id, []byte{byte(id), 1}))
+	}
+	require.Equal(t, []PageRun{{ID: 0, Len: 3}, {ID: 299, Len: 1}}, p.dirty.runs())
+	require.NoError(t, p.Sync())
+
+	// simulate a crash: drop the mapping and the descriptor without closing
+	// the pager
+	require.NoError(t, munmap(p.mmap))
+	require.NoError(t, p.osFile.Close())
+
+	reopened, err := Open(fileName, 16, 0644)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	for _, id := range []uint64{0, 1, 2, 299} {
+		data, err := reopened.Read(id)
+		require.NoError(t, err)
+		require.Equal(t, []byte{byte(id), 1}, data[:2])
+	}
+}
@@ -0,0 +1,51 @@
+package pager
+
+// DetectGaps scans all allocated pages and returns the ids of the ones that
+// look missing: pages containing only zero bytes and pages that can't be
+// read. Since pages are zeroed when allocated, for append-only usage (see
+// WithMonotonicAlloc) it reports the pages that were allocated but never
+// written or whose content got lost, e.g. by a hole in the file.
+func (p *Pager) DetectGaps() []uint64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var gaps []uint64
+	if p.file == nil {
+		return gaps
+	}
+
+	chunkPages := p.streamPages()
+	buf := p.buffers.get(chunkPages)
+	defer p.buffers.put(buf)
+	usable := p.usableSize()
+
+	for id := uint64(0); id < p.count; {
+		k := min(uint64(chunkPages), p.count-id)
+		chunk := buf[:k*uint64(p.pageSize)]
+		if err := p.readPages(chunk, id); err != nil {
+			for i := id; i < id+k; i++ {
+				gaps = append(gaps, i)
+			}
+			id += k
+			continue
+		}
+
+		for i := uint64(0); i < k; i++ {
+			off := i * uint64(p.pageSize)
+			if isZero(chunk[off : off+uint64(usable)]) {
+				gaps = append(gaps, id+i)
+			}
+		}
+		id += k
+	}
+	return gaps
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package pager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_MonotonicAlloc(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644,
+		WithAllocator(NewFreeListAllocator),
+		WithMonotonicAlloc(),
+	)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(4)
+	require.NoError(t, err)
+	require.Error(t, p.FreePage(1))
+
+	require.NoError(t, p.FreePage(3))
+	id, err := p.Alloc(1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), id)
+
+	require.NoError(t, p.Write(0, []byte{1}))
+	require.NoError(t, p.Write(2, []byte{1}))
+	require.Equal(t, []uint64{1, 3}, p.DetectGaps())
+}
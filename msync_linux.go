@@ -0,0 +1,28 @@
+//go:build linux
+
+package pager
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// msync flushes the given range of a shared memory mapping to the file and
+// waits for the writes to complete. The range must start at a multiple of
+// the OS page size.
+func msync(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_MSYNC,
+		uintptr(unsafe.Pointer(&b[0])),
+		uintptr(len(b)),
+		syscall.MS_SYNC,
+	)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
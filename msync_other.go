@@ -0,0 +1,9 @@
+//go:build !linux
+
+package pager
+
+// msync is not available on this platform, the data written through the
+// mapping is flushed by fsync on platforms with a unified buffer cache.
+func msync(b []byte) error {
+	return nil
+}
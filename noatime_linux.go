@@ -0,0 +1,8 @@
+//go:build linux
+
+package pager
+
+import "syscall"
+
+// oNoAtime is the O_NOATIME flag used by WithNoAtime().
+const oNoAtime = syscall.O_NOATIME
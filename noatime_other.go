@@ -0,0 +1,6 @@
+//go:build !linux
+
+package pager
+
+// oNoAtime is zero, O_NOATIME is not available on this platform.
+const oNoAtime = 0
@@ -0,0 +1,365 @@
+package pager
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Option can be passed to Open() to configure optional behaviour of the
+// pager.
+type Option func(opts *options)
+
+// WithSecureFree makes Free overwrite the released pages with zeros before
+// they are removed from the file, so their contents are not left behind.
+func WithSecureFree() Option {
+	return func(opts *options) {
+		opts.secureFree = true
+	}
+}
+
+// WithInitialCapacity preallocates 'bytes' bytes for the buffer of an
+// in-memory pager, avoiding repeated growth while the data is loaded. It's
+// only a performance hint and is ignored for file-backed pagers.
+func WithInitialCapacity(bytes int) Option {
+	return func(opts *options) {
+		opts.initialCapacity = bytes
+	}
+}
+
+// WithReadOnly opens the pager in read-only mode. The file is opened with
+// O_RDONLY, is not created if missing and all mutating operations fail with
+// ErrReadOnly.
+func WithReadOnly() Option {
+	return func(opts *options) {
+		opts.readOnly = true
+	}
+}
+
+// WithOSync opens the file with O_SYNC, so every write returns only after the
+// data has reached the storage device and no explicit fsync is needed. This
+// bypasses the write-back cache of the OS and makes writes considerably
+// slower. It only applies to file-backed pagers and has no effect in
+// read-only mode.
+func WithOSync() Option {
+	return func(opts *options) {
+		opts.osync = true
+	}
+}
+
+// WithReadMmap serves reads of file-backed pagers from a read-only shared
+// memory mapping of the file, while writes still go through the file. Since
+// the mapping is shared, written data is visible through it right away on
+// platforms with a unified buffer cache (Linux, macOS, BSDs). The mapping is
+// replaced whenever the file size changes. It's ignored for other backends
+// and on platforms without mmap support.
+func WithReadMmap() Option {
+	return func(opts *options) {
+		opts.readMmap = true
+	}
+}
+
+// WithNoAtime opens the file with O_NOATIME, so reads don't update the
+// access time of the inode. It's only supported on Linux and only for files
+// owned by the caller (or with CAP_FOWNER); otherwise the file is silently
+// opened without the flag. It has no effect on OpenFile and other backends.
+func WithNoAtime() Option {
+	return func(opts *options) {
+		opts.noAtime = true
+	}
+}
+
+// WithTruncateTrailing makes opening a file whose size isn't a multiple of
+// the page size truncate the trailing partial page instead of failing with
+// ErrTrailingBytes. The trailing bytes are lost. It has no effect in
+// read-only mode.
+func WithTruncateTrailing() Option {
+	return func(opts *options) {
+		opts.truncateTrailing = true
+	}
+}
+
+// WithAllocator makes the pager use the allocator returned by 'newAllocator'
+// instead of the default TruncateAllocator. The function is called once when
+// the pager is created and receives the primitive for growing and shrinking
+// the file.
+func WithAllocator(newAllocator func(space PageSpace) Allocator) Option {
+	return func(opts *options) {
+		opts.newAllocator = newAllocator
+	}
+}
+
+// WithMonotonicAlloc guarantees that Alloc always returns the ids following
+// the last allocated page, so the page ids form a contiguous sequence. It
+// overrides WithAllocator with a TruncateAllocator, i.e. there is no free
+// list: Free and FreePages only accept the pages at the end of the file and
+// the freed ids are handed out again by the next Alloc. Use DetectGaps to
+// check the sequence for missing pages.
+func WithMonotonicAlloc() Option {
+	return func(opts *options) {
+		opts.monotonic = true
+	}
+}
+
+// WithRetry makes the pager retry the ReadAt, WriteAt and Truncate calls on
+// the underlying file that fail with a retryable error (see IsRetryable and
+// WithRetryCondition), up to 'maxAttempts' attempts in total. Before attempt
+// number 'attempt' (starting from 1 for the first retry) the pager sleeps
+// for backoff(attempt) with its lock held. A nil 'backoff' retries
+// immediately. Retries are counted in Stats.
+func WithRetry(maxAttempts int, backoff func(attempt int) time.Duration) Option {
+	return func(opts *options) {
+		opts.retryAttempts = maxAttempts
+		opts.retryBackoff = backoff
+	}
+}
+
+// WithRetryCondition replaces IsRetryable as the function deciding which
+// errors are retried when WithRetry is used.
+func WithRetryCondition(isRetryable func(err error) bool) Option {
+	return func(opts *options) {
+		opts.retryCondition = isRetryable
+	}
+}
+
+// WithWriteMmap enables WithReadMmap() with a writable shared mapping, so
+// writes within the file are copied straight into the mapped memory instead
+// of going through WriteAt. The pages written this way are tracked until
+// the next Sync or Barrier, which flush them with msync(MS_SYNC) before
+// fsyncing the file. It's ignored in read-only mode and together
+// with WithOSync(), whose durability guarantee the mapping would bypass, as
+// well as with WithMmapWindow(), whose windows are read-only.
+func WithWriteMmap() Option {
+	return func(opts *options) {
+		opts.readMmap = true
+		opts.writeMmap = true
+	}
+}
+
+// WithStreamBufferSize sets the number of pages ReadRangeTo and the other
+// methods scanning many pages (Checksum, ChangedSince, DetectGaps) read with
+// a single call, 64 by default. Larger buffers mean fewer syscalls for big
+// scans at the cost of memory. Values below 1 are raised to 1.
+func WithStreamBufferSize(pages int) Option {
+	return func(opts *options) {
+		opts.streamPages = max(pages, 1)
+	}
+}
+
+// WithStrictAlignment makes ReadAt, WriteAt and WriteAtUnsafe fail with
+// ErrMisaligned when the offset or the length of the range is not a
+// multiple of the page size, to catch callers that are expected to work
+// with whole pages only. Off by default, the raw offset methods accept any
+// range.
+func WithStrictAlignment() Option {
+	return func(opts *options) {
+		opts.strictAlignment = true
+	}
+}
+
+// WithAllocGranularity makes the pager allocate and free pages in extents of
+// 'pages' pages: Alloc rounds the requested number of pages up to whole
+// extents and freeing any page frees the whole extent containing it. The
+// free runs tracked by the allocator then consist of whole extents, so
+// fixed-size allocations always find a fitting run. The trade-off is space:
+// up to pages-1 pages are wasted per allocation whose size isn't a multiple
+// of the extent. Extents are aligned to page ids, which holds as long as the
+// file always contained whole extents.
+func WithAllocGranularity(pages int) Option {
+	return func(opts *options) {
+		opts.allocGranularity = pages
+	}
+}
+
+// WithAutoReadAhead makes Read detect sequential access, i.e. reads of the
+// page following the previously read one, and prefetch the next 'pages'
+// pages into the OS page cache in a background goroutine (see Warm). Pages
+// beyond the page count are never prefetched. The goroutine is stopped by
+// Close. It's only used for pagers backed by an os.File.
+func WithAutoReadAhead(pages int) Option {
+	return func(opts *options) {
+		opts.readAhead = pages
+	}
+}
+
+// WithMmapFaultRecovery turns the faults raised when reading from the memory
+// mapping (see WithReadMmap) into a fallback to ReadAt, which then reports
+// a ShortReadError, instead of crashing the process. Faults happen when the
+// mapped file is truncated by another process. It relies on
+// debug.SetPanicOnFault, so it only covers the copies done by the pager and
+// adds a small cost to every mapped read. Windows of WithMmapWindow() are
+// covered as well.
+func WithMmapFaultRecovery() Option {
+	return func(opts *options) {
+		opts.mmapFaultRecovery = true
+	}
+}
+
+// WithFlushPolicy sets when the pager fsyncs the file on its own, see
+// FlushPolicy. 'interval' is the period of FlushInterval and is ignored by
+// the other policies; FlushInterval with a non-positive interval only
+// flushes on Close. Sync and Barrier can be used with every policy. It's
+// ignored in read-only mode and for backends that don't implement Sync.
+func WithFlushPolicy(policy FlushPolicy, interval time.Duration) Option {
+	return func(opts *options) {
+		opts.flushPolicy = policy
+		opts.flushInterval = interval
+	}
+}
+
+// WithGroupCommit makes concurrent Sync calls made within 'window' of each
+// other share a single fsync, see Pager.Sync.
+func WithGroupCommit(window time.Duration) Option {
+	return func(opts *options) {
+		opts.groupCommit = window
+	}
+}
+
+// WithMmapWindow makes WithReadMmap() map the file in windows of 'pages'
+// pages (rounded up to the OS page size) instead of mapping it whole. The
+// windows are mapped on access and the least recently used one is unmapped
+// once more than 8 windows are mapped, which bounds the address space used
+// for huge files. Reads served from the windows are serialized.
+func WithMmapWindow(pages int) Option {
+	return func(opts *options) {
+		opts.mmapWindow = pages
+	}
+}
+
+// WithMmapAdvice passes the expected access pattern to the kernel with
+// madvise every time the file is mapped by WithReadMmap(), including each
+// window of WithMmapWindow(). MmapAdviceRandom disables readahead, which
+// helps random lookups, while MmapAdviceSequential makes scans faster. It's
+// only a performance hint, applied on Linux and ignored elsewhere.
+func WithMmapAdvice(advice MmapAdvice) Option {
+	return func(opts *options) {
+		opts.mmapAdvice = advice
+	}
+}
+
+// WithPageAlignment rounds the physical stride between pages up to a
+// multiple of 'align' bytes, e.g. the sector size of the device, leaving the
+// padding after each page unused. PageSize() stays the page size passed to
+// Open. The alignment is not stored in the file, so the same option must be
+// passed every time the file is opened.
+func WithPageAlignment(align int) Option {
+	return func(opts *options) {
+		opts.pageAlign = align
+	}
+}
+
+// WithPreallocatedBuffers fills the buffer pool used for multi-page reads
+// (see GetBufferN) with 'n' buffers of ReadRangeTo's chunk size when the pager
+// is created, and limits the buffers kept by the pool to 'maxPages' pages
+// (256 by default). Larger buffers are allocated on demand and never pooled.
+func WithPreallocatedBuffers(n, maxPages int) Option {
+	return func(opts *options) {
+		opts.prealloc = n
+		opts.maxPooledPages = maxPages
+	}
+}
+
+// WithScavenger starts a background goroutine that returns the space of free
+// pages to the OS every 'interval'. The free pages at the end of the file are
+// truncated and, for file backends on Linux, holes are punched into runs of
+// at least 'minRun' free pages. It requires an allocator tracking free pages
+// (see FreeTracker), otherwise the scavenger has nothing to do. The
+// goroutine is stopped by Close and is never started in read-only mode.
+func WithScavenger(interval time.Duration, minRun int) Option {
+	return func(opts *options) {
+		opts.scavengeInterval = interval
+		opts.scavengeMinRun = minRun
+	}
+}
+
+// WithPageVersions reserves the last 8 bytes of every page for a version
+// number (e.g. an LSN) stored as big-endian uint64, reducing PageSize() by 8.
+// The version is set by WriteVersioned and returned by ReadVersioned, Write
+// leaves it untouched. Raw access through ReadAt and WriteAt sees the version
+// bytes as part of the page. The pager has no page checksums, so nothing else
+// shares the page trailer. The same setting must be used every time the file
+// is opened.
+func WithPageVersions() Option {
+	return func(opts *options) {
+		opts.versions = true
+	}
+}
+
+// WithLogger makes the pager emit debug logs about its internal decisions
+// (allocations, truncates, remaps, scavenging) through the given logger.
+// Nothing is logged by default.
+func WithLogger(logger *slog.Logger) Option {
+	return func(opts *options) {
+		opts.logger = logger
+	}
+}
+
+// WithTiming makes the pager measure how long the truncates growing the
+// file on Alloc take, reported in Stats. Space reserved with Reserve is
+// consumed without truncating, so those allocations aren't measured.
+func WithTiming() Option {
+	return func(opts *options) {
+		opts.timing = true
+	}
+}
+
+// WithAccessTracking makes the pager count reads and writes per page, see
+// HotPages. It costs memory proportional to the number of accessed pages, up
+// to a fixed cap.
+func WithAccessTracking() Option {
+	return func(opts *options) {
+		opts.accessTracking = true
+	}
+}
+
+// WithReadAllLimit sets the maximum number of bytes ReadAll loads into
+// memory, 64 MiB by default.
+func WithReadAllLimit(bytes int64) Option {
+	return func(opts *options) {
+		opts.readAllLimit = bytes
+	}
+}
+
+type options struct {
+	secureFree       bool
+	initialCapacity  int
+	readOnly         bool
+	osync            bool
+	noAtime          bool
+	readMmap         bool
+	truncateTrailing bool
+	newAllocator     func(space PageSpace) Allocator
+	scavengeInterval time.Duration
+	scavengeMinRun   int
+	versions         bool
+	logger           *slog.Logger
+	accessTracking   bool
+	readAllLimit     int64
+	monotonic        bool
+	retryAttempts    int
+	retryBackoff     func(attempt int) time.Duration
+	retryCondition   func(err error) bool
+	prealloc         int
+	maxPooledPages   int
+	pageAlign        int
+	mmapWindow       int
+	groupCommit      time.Duration
+	writeMmap        bool
+	streamPages      int
+	strictAlignment  bool
+	allocGranularity int
+	readAhead        int
+	flushPolicy      FlushPolicy
+	flushInterval    time.Duration
+	timing           bool
+	mmapAdvice       MmapAdvice
+
+	mmapFaultRecovery bool
+}
+
+func newOptions(opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -1,18 +1,28 @@
 package pager
 
 import (
+	"bytes"
+	"context"
 	"encoding"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var bin = binary.BigEndian
 
 const disableMmap = false
 
+// trimmedPrefixSize is the size of the length prefix used by WriteTrimmed.
+const trimmedPrefixSize = 4
+
 // InMemoryFileName can be passed to Open() to create a pager for an ephemeral
 // in-memory file.
 const InMemoryFileName = ":memory:"
@@ -21,26 +31,203 @@ const InMemoryFileName = ":memory:"
 // pager instance.
 var ErrReadOnly = errors.New("read-only")
 
+// ErrPageOverflow is returned when the data passed to Write doesn't fit into
+// a single page.
+var ErrPageOverflow = errors.New("data larger than page")
+
+// ShortReadError is returned by the read methods when the file returns fewer
+// bytes than requested, e.g. because another process truncated it. It wraps
+// io.EOF.
+type ShortReadError struct {
+	Requested int
+	Got       int
+}
+
+func (e *ShortReadError) Error() string {
+	return fmt.Sprintf("short read: got %d of %d bytes", e.Got, e.Requested)
+}
+
+func (e *ShortReadError) Unwrap() error { return io.EOF }
+
+// shortRead returns the error for a read of 'got' out of 'requested' bytes
+// that failed with 'err': a ShortReadError unless the backend returned an
+// error other than io.EOF.
+func shortRead(requested, got int, err error) error {
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return &ShortReadError{Requested: requested, Got: got}
+}
+
+// ErrMisaligned is returned by ReadAt and WriteAt for ranges not on page
+// boundaries when WithStrictAlignment() is set.
+var ErrMisaligned = errors.New("range not aligned to pages")
+
+// ErrNotAllocated is returned when a write targets the space beyond the
+// allocated pages, even if the file physically contains it.
+var ErrNotAllocated = errors.New("page not allocated")
+
+// ErrTrailingBytes is returned when opening a file with a size that isn't a
+// multiple of the page size, i.e. the last page is only partially present.
+var ErrTrailingBytes = errors.New("file has trailing partial page")
+
+// errNoMmap is returned by mmap when memory mapping is not supported on the
+// platform.
+var errNoMmap = errors.New("mmap not supported")
+
+// errNoFallocate is returned by fallocate when the platform or the filesystem
+// doesn't support it.
+var errNoFallocate = errors.New("fallocate not supported")
+
 // Open opens the named file and returns a pager instance for it. If the file
 // doesn't exist, it will be created if not in read-only mode.
-func Open(fileName string, blockSz int, mode os.FileMode) (*Pager, error) {
+func Open(fileName string, blockSz int, mode os.FileMode, opts ...Option) (*Pager, error) {
+	o := newOptions(opts)
+	return open(fileName, blockSz, mode, o, openFlag(o))
+}
+
+// OpenCtx is Open honoring the cancellation of 'ctx'. Opening a local file
+// doesn't block for long, so the context is checked before opening the file
+// and once more when the pager is ready, in which case the pager is closed
+// again. The context error is returned on cancellation.
+func OpenCtx(ctx context.Context, fileName string, blockSz int, mode os.FileMode, opts ...Option) (*Pager, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p, err := Open(fileName, blockSz, mode, opts...)
+	if err != nil {
+		return nil, err
+	} else if err := ctx.Err(); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// open is Open with parsed options and the flags for os.OpenFile.
+func open(fileName string, blockSz int, mode os.FileMode, o options, flag int) (*Pager, error) {
 	if fileName == InMemoryFileName {
-		return newPager(&inMemory{}, fileName, blockSz)
+		mem := &inMemory{}
+		if o.initialCapacity > 0 {
+			mem.data = make([]byte, 0, o.initialCapacity)
+		}
+		return newPager(mem, fileName, blockSz, o)
 	}
 
-	flag := os.O_CREATE | os.O_RDWR
+	f, err := openFile(fileName, flag, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := newPager(f, fileName, blockSz, o)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// openFlag returns the flags for os.OpenFile according to the options.
+func openFlag(o options) int {
+	flag := os.O_RDONLY
+	if !o.readOnly {
+		flag = os.O_CREATE | os.O_RDWR
+		if o.osync {
+			flag |= os.O_SYNC
+		}
+	}
+	if o.noAtime {
+		flag |= oNoAtime
+	}
+	return flag
+}
 
+// openFile is os.OpenFile retrying without O_NOATIME when the flag is
+// rejected because the caller doesn't own the file.
+func openFile(fileName string, flag int, mode os.FileMode) (*os.File, error) {
 	f, err := os.OpenFile(fileName, flag, mode)
+	if errors.Is(err, os.ErrPermission) && flag&oNoAtime != 0 {
+		f, err = os.OpenFile(fileName, flag&^oNoAtime, mode)
+	}
+	return f, err
+}
+
+// OpenTemp creates a new temporary file in 'dir' using os.CreateTemp with
+// given pattern and returns a pager for it. The file is removed when the
+// pager is closed. Name() returns the generated file name.
+func OpenTemp(dir, pattern string, pageSize int, opts ...Option) (*Pager, error) {
+	f, err := os.CreateTemp(dir, pattern)
 	if err != nil {
 		return nil, err
 	}
 
-	return newPager(f, fileName, blockSz)
+	p, err := newPager(f, f.Name(), pageSize, newOptions(opts))
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	p.temp = true
+	return p, nil
+}
+
+// NewSibling creates a new empty file with given name, using the page size
+// and options of this pager, and returns a pager for it. No pages are
+// copied. The file gets the permissions of this pager's file (0644 for other
+// backends) and the new pager is writable even if this one is read-only. It
+// fails with os.ErrExist if the file already exists.
+func (p *Pager) NewSibling(fileName string) (*Pager, error) {
+	p.mu.RLock()
+	mode := os.FileMode(0644)
+	if p.osFile != nil {
+		if stat, err := p.osFile.Stat(); err == nil {
+			mode = stat.Mode().Perm()
+		}
+	}
+	p.mu.RUnlock()
+
+	o := p.opts
+	o.readOnly = false
+	return open(fileName, p.dataSize, mode, o, openFlag(o)|os.O_EXCL)
+}
+
+// OptimalPageSize rounds 'hint' up to the nearest multiple of the OS page
+// size, returning the OS page size for non-positive hints. Memory mappings
+// are managed by the OS in whole OS pages and O_DIRECT requires aligned
+// offsets and sizes, so pages that are multiples of the OS page size never
+// straddle OS pages and can be read and written without extra work.
+func OptimalPageSize(hint int) int {
+	osPageSize := os.Getpagesize()
+	if hint <= 0 {
+		return osPageSize
+	}
+	return (hint + osPageSize - 1) / osPageSize * osPageSize
+}
+
+// OpenFile returns a pager instance for an already opened file. The pager
+// takes over the file and closes it on Close. Memory mapping can be used just
+// like with Open.
+func OpenFile(f *os.File, pageSize int, opts ...Option) (*Pager, error) {
+	return newPager(f, f.Name(), pageSize, newOptions(opts))
+}
+
+// New returns a pager instance for the given random access file. The file
+// must either be an os.File or implement Sizer so that its size can be found.
+func New(file RandomAccessFile, pageSize int, opts ...Option) (*Pager, error) {
+	return newPager(file, file.Name(), pageSize, newOptions(opts))
+}
+
+// OpenNop returns a pager backed by a NopFile. Page bookkeeping and bounds
+// checks work as usual, but no data is stored.
+func OpenNop(pageSize int, opts ...Option) *Pager {
+	p, _ := New(&NopFile{}, pageSize, opts...)
+	return p
 }
 
 // newPager creates an instance of pager for given random access file object.
 // By default page size is set to the current system page size.
-func newPager(file RandomAccessFile, fileName string, pageSize int) (*Pager, error) {
+func newPager(file RandomAccessFile, fileName string, pageSize int, opts options) (*Pager, error) {
 	size, err := findSize(file)
 	if err != nil {
 		return nil, err
@@ -48,165 +235,665 @@ func newPager(file RandomAccessFile, fileName string, pageSize int) (*Pager, err
 
 	osFile, _ := file.(*os.File)
 
+	var deadliner Deadliner
+	if osFile == nil {
+		deadliner, _ = file.(Deadliner)
+	}
+
+	stride := pageSize
+	if align := opts.pageAlign; align > 0 && stride%align != 0 {
+		stride += align - stride%align
+	}
+
 	p := &Pager{
 		file:     file,
 		fileName: fileName,
 		fileSize: size,
-		pageSize: pageSize,
+		pageSize: stride,
+		dataSize: pageSize,
+		readOnly: opts.readOnly,
 		osFile:   osFile,
+		onDisk:   osFile != nil,
+
+		deadliner: deadliner,
+		logger:    opts.logger,
+
+		readMmap:   opts.readMmap,
+		writeMmap:  opts.writeMmap && !opts.readOnly && !opts.osync && opts.mmapWindow <= 0,
+		secureFree: opts.secureFree,
+		versions:   opts.versions,
+
+		opts: opts,
 	}
 	p.computeCount()
+	p.allocator = p.newAllocator()
+
+	if opts.mmapWindow > 0 && opts.readMmap && osFile != nil {
+		p.windows = newMmapWindows(osFile, opts.mmapWindow*stride)
+		p.windows.advice = opts.mmapAdvice
+	}
+
+	if opts.groupCommit > 0 {
+		p.group = &groupCommit{window: opts.groupCommit}
+	}
+	if opts.timing {
+		p.allocTiming = &durationStats{}
+	}
+
+	p.buffers = newBufferPool(stride, opts.maxPooledPages)
+	p.buffers.fill(opts.prealloc, p.streamPages())
+
+	if opts.accessTracking {
+		p.access = newAccessTracker()
+	}
+
+	if trailing := size % int64(stride); trailing != 0 {
+		if !opts.truncateTrailing || p.readOnly {
+			return nil, fmt.Errorf("%w (size=%d, pageSize=%d)", ErrTrailingBytes, size, stride)
+		}
+
+		p.debug("truncating trailing partial page", "bytes", trailing)
+		if err := p.truncate(size - trailing); err != nil {
+			return nil, err
+		}
+		p.fileSize = size - trailing
+	}
+
+	if err := p.remap(); err != nil {
+		return nil, err
+	}
+
+	if opts.scavengeInterval > 0 && !p.readOnly {
+		p.startScavenger(opts.scavengeInterval, opts.scavengeMinRun)
+	}
+	if opts.readAhead > 0 && p.onDisk {
+		p.startReadAhead(opts.readAhead)
+	}
+	if opts.flushPolicy == FlushInterval && opts.flushInterval > 0 && !p.readOnly {
+		p.startFlusher(opts.flushInterval)
+	}
 
 	return p, nil
 }
 
 // Pager provides facilities for paged I/O on file-like objects with random
-// access. If the underlying file is os.File type, memory mapping will be
-// enabled when file size is non-zero.
+// access. If the underlying file is os.File type and WithReadMmap() is set,
+// reads are served from a read-only memory mapping of the file. With
+// WithWriteMmap() the mapping is writable and serves writes as well.
+//
+// Pager is safe for concurrent use. The memory mapping is never exposed to
+// callers: Read returns a copy and ReadAt copies into the given buffer, so
+// the buffers stay valid after the mapping is replaced by Alloc, Free or any
+// other operation changing the file size. Remapping waits for in-flight reads
+// to finish.
 type Pager struct {
+	// mu guards the state below. Reads share the lock, everything that
+	// changes the file or the memory mapping holds it exclusively.
+	mu sync.RWMutex
+
 	// internal states
 	file     RandomAccessFile
 	fileName string
 	pageSize int
+	dataSize int
 	fileSize int64
 	count    uint64
 	readOnly bool
+	temp     bool
+	onDisk   bool
+
+	// name of the buffer opened with OpenSharedMemory
+	sharedName string
 
 	// memory mapping state for os.File
-	osFile *os.File
+	osFile    *os.File
+	mmap      []byte
+	readMmap  bool
+	writeMmap bool
+	windows   *mmapWindows
+
+	// pages written through the mapping since the last sync, guarded by
+	// syncMu when the pager lock is held shared
+	dirty  pageBitmap
+	syncMu sync.Mutex
+
+	// deadline propagated to backends implementing Deadliner
+	deadliner Deadliner
+	deadline  time.Time
+
+	// page allocation policy
+	allocator Allocator
+	scavenger *scavenger
+
+	// fsyncs the file periodically, nil unless FlushInterval is set
+	flusher *flusher
+
+	// debug logging, nil when disabled
+	logger *slog.Logger
+
+	// optional behaviour
+	secureFree bool
+	versions   bool
+
+	// active snapshots preserving pages before they are modified
+	snapshots []*Snapshot
+
+	// prefetches pages following sequential reads, nil unless enabled
+	readAhead *readAhead
+
+	// coalesces concurrent Sync calls, nil unless enabled
+	group *groupCommit
+
+	// pool of multi-page buffers
+	buffers *bufferPool
+
+	// per-page access counts, nil unless enabled
+	access *accessTracker
+
+	// options the pager was opened with, used when opening related files
+	opts options
 
 	// i/o tracking
-	writes int
-	reads  int
-	allocs int
+	writes    atomic.Int64
+	reads     atomic.Int64
+	allocs    atomic.Int64
+	scavenged atomic.Int64
+	retries   atomic.Int64
+	fsyncs    atomic.Int64
+	remaps    atomic.Int64
+
+	// durations of the truncates growing the file, nil unless WithTiming()
+	allocTiming *durationStats
+
+	// calls made on the backend, see SyscallStats
+	readCalls     atomic.Int64
+	writeCalls    atomic.Int64
+	truncateCalls atomic.Int64
 }
 
 // Alloc allocates 'n' new sequential pages and returns the id of the first
-// page in sequence.
+// page in sequence. The pages are provided by the configured Allocator. With
+// WithAllocGranularity() 'n' is rounded up to whole extents.
 func (p *Pager) Alloc(n int) (uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if p.file == nil {
 		return 0, os.ErrClosed
 	} else if p.readOnly {
 		return 0, ErrReadOnly
+	} else if n <= 0 {
+		return p.count, nil
 	}
+	return p.alloc(n)
+}
 
-	nextID := p.count
+// alloc is Alloc for positive 'n' with the pager locked.
+func (p *Pager) alloc(n int) (uint64, error) {
+	if g := p.opts.allocGranularity; g > 1 && n%g != 0 {
+		n += g - n%g
+	}
 
-	targetSize := p.fileSize + int64(n*p.pageSize)
-	if err := p.file.Truncate(targetSize); err != nil {
+	ids, err := p.allocator.Alloc(n)
+	if err != nil {
 		return 0, err
 	}
+	if p.logger != nil {
+		p.debug("allocated pages", "n", n, "first", ids[0])
+	}
+	for i, id := range ids {
+		if id != ids[0]+uint64(i) {
+			return 0, fmt.Errorf("allocator returned non-sequential pages %v", ids)
+		}
+	}
 
-	p.fileSize = targetSize
-	p.computeCount()
+	p.allocs.Add(1)
+	return ids[0], nil
+}
 
-	p.allocs++
-	return nextID, nil
+// Reserve makes sure that the space for 'pages' pages following the last
+// allocated page is physically available, without changing Count(). The
+// following Alloc calls within the reserved range only bump the count.
+//
+// For os.File backends on Linux the space is reserved using fallocate with
+// FALLOC_FL_KEEP_SIZE, so the file size stays the same and the reservation
+// isn't visible after reopening. Where fallocate is unavailable (other
+// platforms, filesystems not supporting it, or non-os.File backends) the file
+// is grown using Truncate instead. Since the pager doesn't persist the logical
+// count, such a reserved tail is seen as allocated pages after reopening.
+func (p *Pager) Reserve(pages int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	} else if p.readOnly {
+		return ErrReadOnly
+	} else if pages <= 0 {
+		return nil
+	}
+
+	offset := p.offset(p.count)
+	size := int64(pages * p.pageSize)
+
+	if p.osFile != nil {
+		p.applyDeadline()
+		err := fallocate(p.osFile, offset, size)
+		if err == nil {
+			if p.logger != nil {
+				p.debug("reserved space with fallocate", "pages", pages)
+			}
+			return nil
+		} else if !errors.Is(err, errNoFallocate) {
+			return err
+		}
+	}
+
+	if p.logger != nil {
+		p.debug("reserving space by growing the file", "pages", pages)
+	}
+
+	if targetSize := offset + size; targetSize > p.fileSize {
+		if err := p.resize(targetSize); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// Free deallocates 'n' sequential pages from end of file
+// Free deallocates 'n' sequential pages from end of file. With the default
+// TruncateAllocator the file is truncated and any space reserved beyond the
+// last page is released as well.
 func (p *Pager) Free(n int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	} else if p.readOnly {
+		return ErrReadOnly
+	} else if n <= 0 {
+		return nil
+	} else if n > int(p.count) {
+		n = int(p.count)
+	}
+
+	ids := make([]uint64, n)
+	for i := range ids {
+		ids[i] = p.count - uint64(n-i)
+	}
+	return p.freePages(ids)
+}
+
+// FreePage deallocates the page with given id.
+func (p *Pager) FreePage(id uint64) error {
+	return p.FreePages([]uint64{id})
+}
+
+// FreePages deallocates the pages with given ids. With WithSecureFree() the
+// pages are zeroed before being released.
+func (p *Pager) FreePages(ids []uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.freePages(ids)
+}
+
+func (p *Pager) freePages(ids []uint64) error {
 	if p.file == nil {
 		return os.ErrClosed
 	} else if p.readOnly {
 		return ErrReadOnly
+	} else if len(ids) == 0 {
+		return nil
+	}
+
+	for _, id := range ids {
+		if id >= p.count {
+			return fmt.Errorf("invalid page id=%d (max=%d)", id, p.count-1)
+		}
 	}
 
+	ids = p.toExtents(ids)
+	if p.secureFree {
+		if err := p.zeroPages(ids); err != nil {
+			return err
+		}
+	}
+	if p.logger != nil {
+		p.debug("freeing pages", "n", len(ids))
+	}
+	return p.allocator.Free(ids)
+}
+
+// toExtents extends the given page ids to the whole extents containing them
+// when WithAllocGranularity() is set.
+func (p *Pager) toExtents(ids []uint64) []uint64 {
+	g := uint64(p.opts.allocGranularity)
+	if g <= 1 {
+		return ids
+	}
+
+	extents := []uint64{}
+	for _, id := range ids {
+		extents = append(extents, id-id%g)
+	}
+	slices.Sort(extents)
+
+	pages := []uint64{}
+	for _, first := range slices.Compact(extents) {
+		for id := first; id < min(first+g, p.count); id++ {
+			pages = append(pages, id)
+		}
+	}
+	return pages
+}
+
+// zeroPages overwrites given pages with zeros, writing sequential runs of
+// pages at once.
+func (p *Pager) zeroPages(ids []uint64) error {
+	sorted := append([]uint64{}, ids...)
+	slices.Sort(sorted)
+
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && sorted[end] == sorted[end-1]+1 {
+			end++
+		}
+
+		zeros := make([]byte, (end-start)*p.pageSize)
+		if _, err := p.writeAt(zeros, p.offset(sorted[start])); err != nil {
+			return err
+		}
+		p.writes.Add(1)
+		start = end
+	}
+	return nil
+}
+
+// grow appends 'n' pages to the end of the file and returns the id of the
+// first one. Space reserved beyond the logical count is consumed first, the
+// file is only grown when the reservation doesn't cover the request.
+func (p *Pager) grow(n int) (uint64, error) {
+	nextID := p.count
+	if p.logger != nil {
+		p.debug("growing file", "pages", n, "count", p.count)
+	}
+
+	targetSize := p.offset(p.count + uint64(n))
+	if targetSize > p.fileSize {
+		start := time.Now()
+		if err := p.resize(targetSize); err != nil {
+			return 0, err
+		}
+		if p.allocTiming != nil {
+			p.allocTiming.add(time.Since(start))
+		}
+	}
+
+	p.count += uint64(n)
+	return nextID, nil
+}
+
+// shrink removes 'n' pages from the end of the file.
+func (p *Pager) shrink(n int) error {
 	if n > int(p.count) {
 		n = int(p.count)
 	}
-	targetSize := p.fileSize - int64(n*p.pageSize)
+	if p.logger != nil {
+		p.debug("shrinking file", "pages", n, "count", p.count)
+	}
 
-	if err := p.file.Truncate(targetSize); err != nil {
+	if err := p.resize(p.offset(p.count - uint64(n))); err != nil {
 		return err
 	}
 
-	p.fileSize = targetSize
-	p.computeCount()
-
+	p.count -= uint64(n)
 	return nil
 }
 
+// TruncateToCount shrinks the file to exactly Count() pages, dropping any
+// reserved space beyond the last page. Allocated pages are left untouched.
+func (p *Pager) TruncateToCount() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	} else if p.readOnly {
+		return ErrReadOnly
+	}
+
+	return p.resize(p.offset(p.count))
+}
+
 // Read reads one page of data from the underlying file or mmapped region if
 // enabled.
 func (p *Pager) Read(id uint64) ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	if id < 0 || id >= p.count {
 		return nil, fmt.Errorf("invalid page id=%d (max=%d)", id, p.count-1)
 	} else if p.file == nil {
 		return nil, os.ErrClosed
 	}
 
+	data, err := p.readPage(id)
+	if err == nil {
+		p.noteRead(id)
+	}
+	return data, err
+}
+
+// ReadPhysical reads one page like Read, but checks the id against the pages
+// the file physically holds (see CountPhysical) instead of the allocated
+// ones. It bypasses the allocation semantics and is meant for recovering data
+// beyond the allocated pages only.
+func (p *Pager) ReadPhysical(id uint64) ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if max := uint64(p.fileSize) / uint64(p.pageSize); id >= max {
+		return nil, fmt.Errorf("invalid physical page id=%d (count=%d)", id, max)
+	} else if p.file == nil {
+		return nil, os.ErrClosed
+	}
+
+	return p.readPage(id)
+}
+
+// readPage reads the page with given id, excluding the page trailer.
+func (p *Pager) readPage(id uint64) ([]byte, error) {
+	buf, err := p.readRaw(id)
+	if err != nil {
+		return nil, err
+	}
+
+	size := p.usableSize()
+	return buf[:size:size], nil
+}
+
+// readRaw reads the whole physical page with given id.
+func (p *Pager) readRaw(id uint64) ([]byte, error) {
+	buf, err := p.loadPage(id)
+	if err != nil {
+		return nil, err
+	}
+
+	p.reads.Add(1)
+	p.trackAccess(id, false)
+	return buf, nil
+}
+
+// loadPage reads the whole physical page with given id without updating the
+// stats.
+func (p *Pager) loadPage(id uint64) ([]byte, error) {
 	buf := make([]byte, p.pageSize)
 
-	n, err := p.file.ReadAt(buf, p.offset(id))
+	if p.copyMapped(buf, p.offset(id)) {
+		return buf, nil
+	}
+
+	n, err := p.readAt(buf, p.offset(id))
 	if n < p.pageSize {
-		return nil, io.EOF
+		return nil, shortRead(p.pageSize, n, err)
 	}
-	p.reads++
-	return buf, err
+	return buf, nil
 }
 
 // ReadAt reads length count of bytes starting from offset
 func (p *Pager) ReadAt(dst []byte, offset uint64) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	if offset + uint64(len(dst)) > uint64(p.fileSize) {
 		return fmt.Errorf("invalid file offset (filesize=%d, offset=%d)", p.fileSize, offset)
+	} else if err := p.checkAlignment(offset, len(dst)); err != nil {
+		return err
 	} else if p.file == nil {
 		return os.ErrClosed
 	}
 
-	n, err := p.file.ReadAt(dst, int64(offset))
-	if n < len(dst) {
-		return io.EOF
+	if p.copyMapped(dst, int64(offset)) {
+		p.reads.Add(1)
+		return nil
 	}
-	if err != nil {
-		return err
+
+	if n, err := p.readAt(dst, int64(offset)); n < len(dst) {
+		return shortRead(len(dst), n, err)
 	}
-	p.reads++
+	p.reads.Add(1)
 	return nil
 }
 
-// Write writes one page of data to the page with given id. Returns error if
-// the data is larger than a page.
+// Write writes one page of data to the page with given id. Returns an error
+// wrapping ErrPageOverflow if the data is larger than a page.
 func (p *Pager) Write(id uint64, d []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.canWrite(id, len(d)); err != nil {
+		return err
+	}
+	return p.writePage(id, d)
+}
+
+// ZeroPage overwrites the whole page with given id with zeros, including the
+// version when WithPageVersions() is set.
+func (p *Pager) ZeroPage(id uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.canWrite(id, 0); err != nil {
+		return err
+	}
+	return p.writePage(id, make([]byte, p.pageSize))
+}
+
+// canWrite checks whether 'size' bytes of data can be written to the page
+// with given id.
+func (p *Pager) canWrite(id uint64, size int) error {
 	if id < 0 || id >= p.count {
 		return fmt.Errorf("invalid page id=%d (max=%d)", id, p.count-1)
-	} else if len(d) > p.pageSize {
-		return errors.New("data is larger than a page")
+	} else if usable := p.usableSize(); size > usable {
+		return fmt.Errorf("%w (size=%d, max=%d)", ErrPageOverflow, size, usable)
 	} else if p.file == nil {
 		return os.ErrClosed
 	} else if p.readOnly {
 		return ErrReadOnly
 	}
+	return nil
+}
 
-	_, err := p.file.WriteAt(d, p.offset(id))
+func (p *Pager) writePage(id uint64, d []byte) error {
+	_, err := p.writeAt(d, p.offset(id))
 	if err != nil {
 		return err
 	}
-	p.writes++
+	p.writes.Add(1)
+	p.trackAccess(id, true)
 	return nil
 }
 
-// WriteAt writes length count of bytes starting from offset
+// WriteAt writes length count of bytes starting from offset. Returns an error
+// wrapping ErrNotAllocated if the range reaches beyond the allocated pages.
 func (p *Pager) WriteAt(src []byte, offset uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if end := offset + uint64(len(src)); end <= uint64(p.fileSize) && end > uint64(p.offset(p.count)) {
+		return fmt.Errorf("%w (count=%d, offset=%d)", ErrNotAllocated, p.count, offset)
+	}
+	return p.writeAtUnsafe(src, offset)
+}
+
+// WriteAtUnsafe is like WriteAt but only checks the range against the file
+// size, allowing writes into the reserved space beyond the allocated pages.
+func (p *Pager) WriteAtUnsafe(src []byte, offset uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.writeAtUnsafe(src, offset)
+}
+
+func (p *Pager) writeAtUnsafe(src []byte, offset uint64) error {
 	if offset + uint64(len(src)) > uint64(p.fileSize) {
 		return fmt.Errorf("invalid file offset (filesize=%d, offset=%d)", p.fileSize, offset)
+	} else if err := p.checkAlignment(offset, len(src)); err != nil {
+		return err
 	} else if p.file == nil {
 		return os.ErrClosed
 	} else if p.readOnly {
 		return ErrReadOnly
 	}
 
-	n, err := p.file.WriteAt(src, int64(offset))
+	n, err := p.writeAt(src, int64(offset))
 	if n < len(src) {
 		return io.EOF
 	}
 	if err != nil {
 		return err
 	}
-	p.writes++
+	p.writes.Add(1)
 	return nil
 }
 
+// checkAlignment checks that the range of 'size' bytes at 'offset' covers
+// whole pages if WithStrictAlignment() is set.
+func (p *Pager) checkAlignment(offset uint64, size int) error {
+	if !p.opts.strictAlignment {
+		return nil
+	} else if offset%uint64(p.pageSize) != 0 || size%p.pageSize != 0 {
+		return fmt.Errorf("%w (offset=%d, size=%d, pageSize=%d)", ErrMisaligned, offset, size, p.pageSize)
+	}
+	return nil
+}
+
+// WriteTrimmed writes the data prefixed by its 4 byte length to the page with
+// given id, so that ReadTrimmed can return exactly the written bytes.
+func (p *Pager) WriteTrimmed(id uint64, d []byte) error {
+	buf := make([]byte, trimmedPrefixSize+len(d))
+	bin.PutUint32(buf, uint32(len(d)))
+	copy(buf[trimmedPrefixSize:], d)
+	return p.Write(id, buf)
+}
+
+// ReadTrimmed reads the page with given id written by WriteTrimmed and
+// returns only the data without the length prefix.
+func (p *Pager) ReadTrimmed(id uint64) ([]byte, error) {
+	buf, err := p.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	size := bin.Uint32(buf)
+	if uint64(size) > uint64(len(buf)-trimmedPrefixSize) {
+		return nil, fmt.Errorf("invalid length prefix %d in page %d", size, id)
+	}
+	return buf[trimmedPrefixSize : trimmedPrefixSize+size], nil
+}
+
 // Marshal writes the marshaled value of 'v' into page with given id.
 func (p *Pager) Marshal(id uint64, v encoding.BinaryMarshaler) error {
 	d, err := v.MarshalBinary()
@@ -226,43 +913,326 @@ func (p *Pager) Unmarshal(id uint64, into encoding.BinaryUnmarshaler) error {
 	return into.UnmarshalBinary(d)
 }
 
-// PageSize returns the size of one page used by pager.
-func (p *Pager) PageSize() int { return p.pageSize }
+// WriteStruct encodes 'v' with binary.Write, using the big-endian byte order
+// of the pager, and writes it into page with given id. Only fixed-size
+// values are supported, see encoding/binary. It fails with ErrPageOverflow
+// if the encoded value doesn't fit into the page.
+func (p *Pager) WriteStruct(id uint64, v any) error {
+	if size := binary.Size(v); size < 0 {
+		return fmt.Errorf("value of type %T has no fixed size", v)
+	}
 
-// Count returns the number of pages in the underlying file. Returns error if
-// the file is closed.
-func (p *Pager) Count() uint64 { return p.count }
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, bin, v); err != nil {
+		return err
+	}
+	return p.Write(id, buf.Bytes())
+}
+
+// ReadStruct reads the page with given id and decodes its data into 'out'
+// with binary.Read. Only fixed-size values are supported, see
+// encoding/binary. It fails with ErrPageOverflow if the encoded size of
+// 'out' exceeds the page.
+func (p *Pager) ReadStruct(id uint64, out any) error {
+	size := binary.Size(out)
+	if size < 0 {
+		return fmt.Errorf("value of type %T has no fixed size", out)
+	} else if usable := p.usableSize(); size > usable {
+		return fmt.Errorf("%w (size=%d, max=%d)", ErrPageOverflow, size, usable)
+	}
+
+	d, err := p.Read(id)
+	if err != nil {
+		return err
+	}
+	return binary.Read(bytes.NewReader(d), bin, out)
+}
+
+// Name returns the name of the underlying file, InMemoryFileName for
+// in-memory pagers. It's available after Close as well.
+func (p *Pager) Name() string { return p.fileName }
+
+// PageSize returns the number of bytes of one page available for data. It's
+// less than the page size passed to Open when the page trailer is enabled
+// (see WithPageVersions).
+func (p *Pager) PageSize() int { return p.usableSize() }
+
+// Fits returns true if 'data' fits into a single page, i.e. Write won't
+// fail with ErrPageOverflow for it. The page trailer of WithPageVersions()
+// is the only overhead taken from the page. It does no I/O.
+func (p *Pager) Fits(data []byte) bool { return len(data) <= p.usableSize() }
+
+// Count returns the number of allocated pages. It's an alias for
+// CountAllocated.
+func (p *Pager) Count() uint64 { return p.CountAllocated() }
+
+// CountAllocated returns the number of allocated pages, i.e. the pages with
+// ids from 0 to CountAllocated()-1 that can be read and written.
+func (p *Pager) CountAllocated() uint64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.count
+}
+
+// CountPhysical returns the number of whole pages the underlying file can
+// physically hold, i.e. the file size divided by the page size. It's greater
+// than CountAllocated only when the file has a tail beyond the last
+// allocated page: when Reserve fell back to Truncate (it doesn't when
+// fallocate reserves the space without changing the file size, as for files
+// on Linux) or after SetHighWaterMark lowered the count. A fresh pager
+// derives its count from the file size, so such a tail left behind by a
+// crash is seen as allocated pages after reopening.
+func (p *Pager) CountPhysical() uint64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return uint64(p.fileSize) / uint64(p.pageSize)
+}
+
+// HighWaterMark returns the number of pages ever allocated and not truncated
+// away. Freed pages tracked by a free list stay below the mark, so it's the
+// same as CountAllocated and the number of live pages is HighWaterMark()
+// minus the free pages.
+func (p *Pager) HighWaterMark() uint64 { return p.CountAllocated() }
+
+// SetHighWaterMark sets the number of allocated pages to 'n' during
+// recovery, e.g. when the count derived from the file size is known to be
+// wrong. The file is grown with zeroed pages if it can't hold 'n' pages,
+// but never shrunk, so the pages above the new mark stay readable with
+// ReadPhysical. The allocator is recreated, dropping all free pages it
+// tracked, and allocation continues from the new mark.
+func (p *Pager) SetHighWaterMark(n uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	} else if p.readOnly {
+		return ErrReadOnly
+	}
+
+	if size := p.offset(n); size > p.fileSize {
+		if err := p.resize(size); err != nil {
+			return err
+		}
+	}
+
+	if p.logger != nil {
+		p.debug("setting high-water mark", "from", p.count, "to", n)
+	}
+	p.count = n
+	p.allocator = p.newAllocator()
+	if p.scavenger != nil {
+		p.scavenger.punched = map[uint64]struct{}{}
+	}
+	return nil
+}
+
+// PhysicalOffset returns the byte offset within the file where the page
+// with given id starts. There is no header and no indirection, so it's
+// id times the page stride, which includes the padding added by
+// WithPageAlignment(). The page trailer (see WithPageVersions) is stored at
+// the end of the page. It fails for ids beyond the allocated pages.
+func (p *Pager) PhysicalOffset(id uint64) (int64, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.file == nil {
+		return 0, os.ErrClosed
+	} else if id >= p.count {
+		return 0, fmt.Errorf("invalid page id=%d (max=%d)", id, p.count-1)
+	}
+	return p.offset(id), nil
+}
+
+// IsAligned returns true if the page size is a multiple of the OS page size,
+// see OptimalPageSize.
+func (p *Pager) IsAligned() bool { return p.pageSize%os.Getpagesize() == 0 }
 
 // ReadOnly returns true if the pager instance is in read-only mode.
-func (p *Pager) ReadOnly() bool { return p.readOnly }
+func (p *Pager) ReadOnly() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.readOnly
+}
+
+// SetReadOnly switches the pager to read-only mode for good, after which all
+// mutating operations fail with ErrReadOnly. The scavenger and the periodic
+// flush of FlushInterval are stopped, pending writes are flushed with an
+// fsync and, for os.File backends, the file is reopened by name with
+// O_RDONLY and the memory mapping is replaced by a read-only one, so the
+// file must not have been renamed meanwhile. There is no way back to
+// read-write mode. It's a no-op for pagers already in read-only mode.
+func (p *Pager) SetReadOnly() error {
+	p.stopScavenger()
+	p.stopFlusher()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	} else if p.readOnly {
+		return nil
+	}
+
+	if err := p.sync(); err != nil {
+		return err
+	}
+
+	if p.osFile != nil {
+		o := p.opts
+		o.readOnly = true
+		f, err := openFile(p.fileName, openFlag(o), 0)
+		if err != nil {
+			return err
+		}
+
+		p.unmap()
+		p.file.Close()
+		p.file = f
+		p.osFile = f
+		if p.windows != nil {
+			p.windows.file = f
+		}
+		p.writeMmap = false
+		p.readOnly = true
+		return p.remap()
+	}
 
+	p.readOnly = true
+	return nil
+}
+
+// Files returns the names of all the files on disk belonging to the pager.
+// Currently that's only the main file, there are no sidecar files. It's
+// empty for pagers not backed by an os.File, e.g. in-memory ones.
+func (p *Pager) Files() []string {
+	if !p.onDisk {
+		return []string{}
+	}
+	return []string{p.fileName}
+}
+
+// ModTime returns the modification time of the underlying file, e.g. to
+// detect changes made by other processes. It returns errors.ErrUnsupported
+// for pagers not backed by an os.File.
+func (p *Pager) ModTime() (time.Time, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.file == nil {
+		return time.Time{}, os.ErrClosed
+	} else if p.osFile == nil {
+		return time.Time{}, errors.ErrUnsupported
+	}
+
+	stat, err := p.osFile.Stat()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return stat.ModTime(), nil
+}
+
+// Remove closes the pager and deletes all the files returned by Files. For
+// pagers opened with OpenSharedMemory the buffer is deleted from the
+// registry instead.
 func (p *Pager) Remove() {
-	p.file.Close()
-	os.Remove(p.fileName)
+	p.Close()
+	for _, name := range p.Files() {
+		os.Remove(name)
+	}
+	if p.sharedName != "" {
+		removeSharedMemory(p.sharedName)
+	}
 }
 
 // Close closes the underlying file and marks the pager as closed for use.
+// Files created by OpenTemp are removed as well.
 func (p *Pager) Close() error {
+	p.stopScavenger()
+	p.stopReadAhead()
+	p.stopFlusher()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if p.file == nil {
 		return nil
 	}
 
+	flushErr := p.flushOnClose()
+	p.unmap()
 	err := p.file.Close()
+	if err == nil {
+		err = flushErr
+	}
 	p.osFile = nil
 	p.file = nil
+
+	if p.temp {
+		if rmErr := os.Remove(p.fileName); err == nil {
+			err = rmErr
+		}
+	}
 	return err
 }
 
 // Stats returns i/o stats collected by this pager.
 func (p *Pager) Stats() Stats {
-	return Stats{
-		Allocs: p.allocs,
-		Reads:  p.reads,
-		Writes: p.writes,
+	stats := Stats{
+		Allocs: int(p.allocs.Load()),
+		Reads:  int(p.reads.Load()),
+		Writes: int(p.writes.Load()),
+
+		Scavenged: int(p.scavenged.Load()),
+		Retries:   int(p.retries.Load()),
+		Fsyncs:    int(p.fsyncs.Load()),
+
+		MmapRemaps: int(p.remaps.Load()),
+	}
+	if w := p.windows; w != nil {
+		stats.CacheHits = int(w.hits.Load())
+		stats.CacheMisses = int(w.misses.Load())
+		stats.Evictions = int(w.evictions.Load())
+	}
+	if t := p.allocTiming; t != nil {
+		stats.AllocTruncates, stats.AllocTruncateMin, stats.AllocTruncateAvg, stats.AllocTruncateMax = t.load()
+	}
+	return stats
+}
+
+// SyscallStats returns the number of calls made on the underlying file, as
+// opposed to the logical operations counted by Stats. Reads served from the
+// memory mapping and writes copied into it (see WithReadMmap and
+// WithWriteMmap) make no calls, while a single logical operation can make
+// several, e.g. when retried (see WithRetry). For os.File backends every
+// call is a syscall.
+func (p *Pager) SyscallStats() SyscallStats {
+	return SyscallStats{
+		ReadAt:   int(p.readCalls.Load()),
+		WriteAt:  int(p.writeCalls.Load()),
+		Truncate: int(p.truncateCalls.Load()),
+		Fsync:    int(p.fsyncs.Load()),
+	}
+}
+
+// Overhead returns the storage overhead of the on-disk layout used by the
+// pager, as configured by the enabled options.
+func (p *Pager) Overhead() OverheadStats {
+	usable := p.usableSize()
+	return OverheadStats{
+		PageOverhead:   p.pageSize - usable,
+		UsableFraction: float64(usable) / float64(p.pageSize),
 	}
 }
 
 func (p *Pager) String() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	if p.file == nil {
 		return fmt.Sprintf("Pager{closed=true}")
 	}
@@ -273,6 +1243,162 @@ func (p *Pager) String() string {
 	)
 }
 
+// SetDeadline sets the deadline for the following I/O operations of the
+// pager. If the underlying file implements Deadliner, the deadline is passed
+// to it before each I/O call, otherwise (e.g. for os.File) this is a no-op.
+// A zero value of 't' means no deadline.
+func (p *Pager) SetDeadline(t time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.deadline = t
+}
+
+func (p *Pager) applyDeadline() {
+	if p.deadliner != nil {
+		p.deadliner.SetDeadline(p.deadline)
+	}
+}
+
+func (p *Pager) readAt(b []byte, off int64) (n int, err error) {
+	err = p.retry(func() error {
+		p.applyDeadline()
+		p.readCalls.Add(1)
+		n, err = p.file.ReadAt(b, off)
+		return err
+	})
+	return n, err
+}
+
+func (p *Pager) writeAt(b []byte, off int64) (int, error) {
+	if err := p.preserveRange(off, int64(len(b))); err != nil {
+		return 0, err
+	}
+
+	if p.writeMmap && p.mmap != nil && off+int64(len(b)) <= int64(len(p.mmap)) {
+		copy(p.mmap[off:], b)
+		p.dirty.markRange(uint64(off)/uint64(p.pageSize), uint64(off+int64(len(b))-1)/uint64(p.pageSize))
+		return len(b), p.flushOnWrite()
+	}
+
+	var n int
+	err := p.retry(func() (err error) {
+		p.applyDeadline()
+		p.writeCalls.Add(1)
+		n, err = p.file.WriteAt(b, off)
+		return err
+	})
+	if err == nil {
+		err = p.flushOnWrite()
+	}
+	return n, err
+}
+
+func (p *Pager) truncate(size int64) error {
+	return p.retry(func() error {
+		p.applyDeadline()
+		p.truncateCalls.Add(1)
+		return p.file.Truncate(size)
+	})
+}
+
+// resize truncates the file to given size and updates the memory mapping.
+func (p *Pager) resize(size int64) error {
+	if size < p.fileSize {
+		if err := p.preserveRange(size, p.fileSize-size); err != nil {
+			return err
+		}
+	}
+
+	if p.logger != nil {
+		p.debug("truncating file", "from", p.fileSize, "to", size)
+	}
+	if err := p.truncate(size); err != nil {
+		return err
+	}
+	p.fileSize = size
+	return p.remap()
+}
+
+// remap maps the whole file into memory if read mmap is enabled, replacing
+// the previous mapping. Platforms without mmap support fall back to ReadAt.
+func (p *Pager) remap() error {
+	if disableMmap || !p.readMmap || p.osFile == nil {
+		return nil
+	}
+
+	if err := p.unmap(); err != nil {
+		return err
+	} else if p.fileSize == 0 {
+		return nil
+	}
+
+	if p.windows != nil {
+		p.windows.reset(p.fileSize)
+		return nil
+	}
+
+	if p.logger != nil {
+		p.debug("remapping file", "size", p.fileSize)
+	}
+	data, err := mmap(p.osFile, 0, p.fileSize, p.writeMmap)
+	if err != nil && p.writeMmap && !errors.Is(err, errNoMmap) {
+		p.debug("writable mmap failed, falling back to WriteAt", "err", err)
+		p.writeMmap = false
+		data, err = mmap(p.osFile, 0, p.fileSize, false)
+	}
+	if errors.Is(err, errNoMmap) {
+		p.debug("mmap not supported, falling back to ReadAt")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := advise(data, p.opts.mmapAdvice); err != nil {
+		p.debug("madvise failed", "err", err)
+	}
+	p.mmap = data
+	p.remaps.Add(1)
+	return nil
+}
+
+func (p *Pager) unmap() error {
+	if p.windows != nil {
+		return p.windows.reset(0)
+	} else if p.mmap == nil {
+		return nil
+	}
+
+	err := munmap(p.mmap)
+	p.mmap = nil
+	return err
+}
+
+// usableSize returns the number of bytes of a page available for data.
+func (p *Pager) usableSize() int {
+	if p.versions {
+		return p.dataSize - pageVersionSize
+	}
+	return p.dataSize
+}
+
+// debug logs a debug message if a logger is set via WithLogger(). The
+// arguments are boxed before the check, so calls on common paths are guarded
+// by 'p.logger != nil' to cost nothing without a logger.
+func (p *Pager) debug(msg string, args ...any) {
+	if p.logger != nil {
+		p.logger.Debug(msg, args...)
+	}
+}
+
+// newAllocator creates the allocator configured with WithAllocator() or the
+// default one.
+func (p *Pager) newAllocator() Allocator {
+	if p.opts.newAllocator != nil && !p.opts.monotonic {
+		return p.opts.newAllocator(pageSpace{p})
+	}
+	return NewTruncateAllocator(pageSpace{p})
+}
+
 func (p *Pager) computeCount() {
 	p.count = uint64(p.fileSize) / uint64(p.pageSize)
 }
@@ -286,11 +1412,68 @@ type Stats struct {
 	Writes int
 	Reads  int
 	Allocs int
+
+	// Scavenged is the number of free pages whose space has been returned
+	// to the OS by the scavenger.
+	Scavenged int
+
+	// Retries is the number of I/O calls retried because of WithRetry.
+	Retries int
+
+	// Fsyncs is the number of fsyncs issued on the underlying file.
+	Fsyncs int
+
+	// MmapRemaps is the number of times the whole file has been mapped into
+	// memory, see WithReadMmap.
+	MmapRemaps int
+
+	// CacheHits and CacheMisses count the accesses to the mapped windows of
+	// WithMmapWindow(), the pager has no other cache. Evictions is the
+	// number of windows unmapped to make room for others. All three stay 0
+	// unless windowed mapping is enabled.
+	CacheHits   int
+	CacheMisses int
+	Evictions   int
+
+	// AllocTruncates is the number of truncates issued by Alloc to grow the
+	// file, and AllocTruncateMin, AllocTruncateAvg and AllocTruncateMax
+	// their durations. All of them stay 0 unless WithTiming() is set.
+	AllocTruncates   int
+	AllocTruncateMin time.Duration
+	AllocTruncateAvg time.Duration
+	AllocTruncateMax time.Duration
 }
 
 func (s Stats) String() string {
 	return fmt.Sprintf(
-		"Stats{writes=%d, allocs=%d, reads=%d}",
-		s.Writes, s.Allocs, s.Reads,
+		"Stats{writes=%d, allocs=%d, reads=%d, scavenged=%d, retries=%d, fsyncs=%d, "+
+			"mmapRemaps=%d, cacheHits=%d, cacheMisses=%d, evictions=%d, "+
+			"allocTruncates=%d, allocTruncateMin=%v, allocTruncateAvg=%v, allocTruncateMax=%v}",
+		s.Writes, s.Allocs, s.Reads, s.Scavenged, s.Retries, s.Fsyncs,
+		s.MmapRemaps, s.CacheHits, s.CacheMisses, s.Evictions,
+		s.AllocTruncates, s.AllocTruncateMin, s.AllocTruncateAvg, s.AllocTruncateMax,
 	)
 }
+
+// SyscallStats represents the calls made by the pager on the underlying
+// file, see Pager.SyscallStats.
+type SyscallStats struct {
+	ReadAt   int
+	WriteAt  int
+	Truncate int
+	Fsync    int
+}
+
+// OverheadStats describes the space used by the pager for its own needs.
+type OverheadStats struct {
+	// HeaderBytes is the size of the header at the beginning of the file.
+	HeaderBytes int
+	// PageOverhead is the number of bytes of each page not available for
+	// data.
+	PageOverhead int
+	// MetadataPages is the number of pages used to store metadata.
+	MetadataPages uint64
+	// UsableFraction is the fraction of the physical bytes of a page that
+	// is available for data.
+	UsableFraction float64
+}
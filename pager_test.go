@@ -2,15 +2,21 @@ package pager
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestPager(t *testing.T) {
 	filename := "test.bin"
-	require.NoError(t, os.Remove(filename))
+	os.Remove(filename)
 	defer os.Remove(filename)
 
 	p, err := Open(filename, os.Getpagesize(), 0644)
@@ -27,3 +33,789 @@ func TestPager(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 0, bytes.Compare(data, readData[:len(data)]))
 }
+
+func TestPager_Reserve(t *testing.T) {
+	for _, name := range []string{InMemoryFileName, "test_reserve.bin"} {
+		os.Remove(name)
+
+		p, err := Open(name, 128, 0644)
+		require.NoError(t, err)
+
+		_, err = p.Alloc(1)
+		require.NoError(t, err)
+
+		require.NoError(t, p.Reserve(4))
+		require.Equal(t, uint64(1), p.Count())
+
+		id, err := p.Alloc(2)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), id)
+		require.Equal(t, uint64(3), p.Count())
+		require.NoError(t, p.Write(2, []byte{1, 2, 3}))
+
+		require.NoError(t, p.Free(3))
+		require.Equal(t, uint64(0), p.Count())
+
+		p.Remove()
+	}
+}
+
+func TestPager_Write_Overflow(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	id, err := p.Alloc(1)
+	require.NoError(t, err)
+
+	err = p.Write(id, make([]byte, 17))
+	require.ErrorIs(t, err, ErrPageOverflow)
+	require.NoError(t, p.Write(id, make([]byte, 16)))
+}
+
+func TestPager_Fits(t *testing.T) {
+	require.True(t, OpenNop(16).Fits(make([]byte, 16)))
+	require.False(t, OpenNop(16).Fits(make([]byte, 17)))
+
+	p, err := Open(InMemoryFileName, 16, 0644, WithPageVersions())
+	require.NoError(t, err)
+	defer p.Close()
+
+	require.True(t, p.Fits(nil))
+	require.True(t, p.Fits(make([]byte, 8)))
+	require.False(t, p.Fits(make([]byte, 9)))
+}
+
+func TestPager_ZeroPage(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644, WithSecureFree())
+	require.NoError(t, err)
+	defer p.Close()
+
+	id, err := p.Alloc(2)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(id, []byte{1, 2, 3}))
+
+	require.NoError(t, p.ZeroPage(id))
+	data, err := p.Read(id)
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, 16), data)
+
+	require.NoError(t, p.Free(2))
+	require.Equal(t, uint64(0), p.Count())
+	require.Equal(t, 3, p.Stats().Writes)
+}
+
+func TestPager_SyscallStats(t *testing.T) {
+	p, err := Open(filepath.Join(t.TempDir(), "syscalls.bin"), 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(4)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(1, []byte{1}))
+	_, err = p.ReadRangeTo(io.Discard, 0, 4)
+	require.NoError(t, err)
+	_, err = p.Read(1)
+	require.NoError(t, err)
+	require.NoError(t, p.Sync())
+
+	require.Equal(t, 5, p.Stats().Reads)
+	require.Equal(t, SyscallStats{ReadAt: 2, WriteAt: 1, Truncate: 1, Fsync: 1}, p.SyscallStats())
+}
+
+func TestPager_InitialCapacity(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644, WithInitialCapacity(64))
+	require.NoError(t, err)
+	defer p.Close()
+
+	id, err := p.Alloc(2)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(id+1, []byte{1, 2, 3}))
+	require.NoError(t, p.Free(1))
+
+	id, err = p.Alloc(1)
+	require.NoError(t, err)
+	data, err := p.Read(id)
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, 16), data)
+}
+
+type sizerFile struct {
+	inMemory
+}
+
+func (f *sizerFile) Size() (int64, error) { return int64(len(f.data)), nil }
+
+func TestNew_Sizer(t *testing.T) {
+	f := &sizerFile{inMemory{data: make([]byte, 48)}}
+
+	p, err := New(f, 16)
+	require.NoError(t, err)
+	defer p.Close()
+	require.Equal(t, uint64(3), p.Count())
+}
+
+func TestPager_ReadOnly(t *testing.T) {
+	filename := "test_readonly.bin"
+	os.Remove(filename)
+	defer os.Remove(filename)
+
+	_, err := Open(filename, 16, 0644, WithReadOnly())
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	p, err := Open(filename, 16, 0644, WithOSync())
+	require.NoError(t, err)
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(0, []byte{1}))
+	require.NoError(t, p.Close())
+
+	p, err = Open(filename, 16, 0644, WithReadOnly(), WithOSync())
+	require.NoError(t, err)
+	defer p.Close()
+	require.True(t, p.ReadOnly())
+	require.Equal(t, uint64(1), p.Count())
+
+	_, err = p.Alloc(1)
+	require.ErrorIs(t, err, ErrReadOnly)
+	require.ErrorIs(t, p.Write(0, []byte{2}), ErrReadOnly)
+}
+
+func TestPager_NoAtime(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "noatime.bin")
+	require.Equal(t, os.O_RDONLY|oNoAtime, openFlag(options{noAtime: true, readOnly: true}))
+
+	p, err := Open(filename, 16, 0644, WithNoAtime())
+	require.NoError(t, err)
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(0, []byte{1}))
+	require.NoError(t, p.Close())
+
+	p, err = Open(filename, 16, 0644, WithNoAtime(), WithReadOnly())
+	require.NoError(t, err)
+	defer p.Close()
+
+	data, err := p.Read(0)
+	require.NoError(t, err)
+	require.Equal(t, byte(1), data[0])
+}
+
+func TestPager_SetReadOnly(t *testing.T) {
+	for _, fileName := range []string{InMemoryFileName, filepath.Join(t.TempDir(), "test.bin")} {
+		p, err := Open(fileName, 16, 0644, WithWriteMmap())
+		require.NoError(t, err)
+		defer p.Close()
+
+		_, err = p.Alloc(2)
+		require.NoError(t, err)
+		require.NoError(t, p.Write(1, []byte{1}))
+
+		require.NoError(t, p.SetReadOnly())
+		require.NoError(t, p.SetReadOnly())
+		require.True(t, p.ReadOnly())
+
+		require.ErrorIs(t, p.Write(1, []byte{2}), ErrReadOnly)
+		_, err = p.Alloc(1)
+		require.ErrorIs(t, err, ErrReadOnly)
+		if p.osFile != nil {
+			_, err = p.osFile.WriteAt([]byte{2}, 16)
+			require.Error(t, err, "file not reopened read-only")
+		}
+
+		data, err := p.Read(1)
+		require.NoError(t, err)
+		require.Equal(t, byte(1), data[0])
+	}
+}
+
+type deadlineFile struct {
+	sizerFile
+	deadlines []time.Time
+}
+
+func (f *deadlineFile) SetDeadline(t time.Time) error {
+	f.deadlines = append(f.deadlines, t)
+	return nil
+}
+
+func TestPager_ShortRead(t *testing.T) {
+	f := &sizerFile{inMemory{data: make([]byte, 48)}}
+	p, err := New(f, 16)
+	require.NoError(t, err)
+	defer p.Close()
+
+	f.data = f.data[:20]
+
+	_, err = p.Read(1)
+	require.ErrorIs(t, err, io.EOF)
+	var short *ShortReadError
+	require.ErrorAs(t, err, &short)
+	require.Equal(t, ShortReadError{Requested: 16, Got: 4}, *short)
+
+	err = p.ReadAt(make([]byte, 8), 32)
+	require.ErrorAs(t, err, &short)
+	require.Equal(t, ShortReadError{Requested: 8, Got: 0}, *short)
+}
+
+func TestPager_SetDeadline(t *testing.T) {
+	f := &deadlineFile{}
+
+	p, err := New(f, 16)
+	require.NoError(t, err)
+	defer p.Close()
+
+	deadline := time.Now().Add(time.Second)
+	p.SetDeadline(deadline)
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(0, []byte{1}))
+	_, err = p.Read(0)
+	require.NoError(t, err)
+
+	require.Equal(t, []time.Time{deadline, deadline, deadline}, f.deadlines)
+}
+
+func TestPager_CountPhysical(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), p.CountAllocated())
+	require.Equal(t, uint64(1), p.CountPhysical())
+
+	require.NoError(t, p.Reserve(4))
+	require.Equal(t, uint64(1), p.Count())
+	require.Equal(t, uint64(1), p.CountAllocated())
+	require.Equal(t, uint64(5), p.CountPhysical())
+
+	_, err = p.Alloc(2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), p.CountAllocated())
+	require.Equal(t, uint64(5), p.CountPhysical())
+
+	_, err = p.Alloc(3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), p.CountAllocated())
+	require.Equal(t, uint64(6), p.CountPhysical())
+}
+
+func TestPager_ReadMmap(t *testing.T) {
+	filename := "test_mmap.bin"
+	os.Remove(filename)
+	defer os.Remove(filename)
+
+	p, err := Open(filename, 64, 0644, WithReadMmap())
+	require.NoError(t, err)
+	defer p.Close()
+
+	for i := 0; i < 3; i++ {
+		id, err := p.Alloc(1)
+		require.NoError(t, err)
+		require.NoError(t, p.Write(id, []byte{byte(i + 1)}))
+		require.NotNil(t, p.mmap)
+	}
+
+	require.NoError(t, p.Write(1, []byte{42}))
+	require.NoError(t, p.Free(1))
+
+	for i, want := range []byte{1, 42} {
+		data, err := p.Read(uint64(i))
+		require.NoError(t, err)
+		require.Equal(t, want, data[0])
+	}
+
+	dst := make([]byte, 2)
+	require.NoError(t, p.ReadAt(dst, 63))
+	require.Equal(t, []byte{0, 42}, dst)
+}
+
+func TestPager_TruncateToCount(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(2)
+	require.NoError(t, err)
+	require.NoError(t, p.Reserve(3))
+	require.Equal(t, uint64(5), p.CountPhysical())
+
+	require.NoError(t, p.Write(1, []byte{7}))
+	require.NoError(t, p.TruncateToCount())
+	require.Equal(t, uint64(2), p.CountPhysical())
+
+	data, err := p.Read(1)
+	require.NoError(t, err)
+	require.Equal(t, byte(7), data[0])
+}
+
+func TestPager_CountPhysical_File(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "physical.bin")
+	p, err := Open(filename, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.NoError(t, p.Reserve(4))
+
+	// fallocate reserves the space without growing the file, the counts only
+	// diverge when Reserve falls back to Truncate
+	expected := uint64(1)
+	if fallocate(p.osFile, p.offset(1), p.offset(4)) == errNoFallocate {
+		expected = 5
+	}
+	stat, err := os.Stat(filename)
+	require.NoError(t, err)
+	require.Equal(t, uint64(stat.Size()/16), p.CountPhysical())
+	require.Equal(t, expected, p.CountPhysical())
+	require.Equal(t, uint64(1), p.CountAllocated())
+
+	// lowering the high-water mark leaves a tail
+	_, err = p.Alloc(4)
+	require.NoError(t, err)
+	require.NoError(t, p.SetHighWaterMark(2))
+	require.Equal(t, uint64(5), p.CountPhysical())
+	require.Equal(t, uint64(2), p.CountAllocated())
+}
+
+func TestOpen_TrailingBytes(t *testing.T) {
+	filename := "test_trailing.bin"
+	os.Remove(filename)
+	defer os.Remove(filename)
+
+	require.NoError(t, os.WriteFile(filename, make([]byte, 40), 0644))
+
+	_, err := Open(filename, 16, 0644)
+	require.ErrorIs(t, err, ErrTrailingBytes)
+
+	p, err := Open(filename, 16, 0644, WithTruncateTrailing())
+	require.NoError(t, err)
+	defer p.Close()
+	require.Equal(t, uint64(2), p.Count())
+
+	stat, err := os.Stat(filename)
+	require.NoError(t, err)
+	require.Equal(t, int64(32), stat.Size())
+}
+
+func TestOpenNop(t *testing.T) {
+	p := OpenNop(16)
+	defer p.Close()
+
+	id, err := p.Alloc(2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), p.Count())
+	require.NoError(t, p.Write(id+1, []byte{1}))
+
+	_, err = p.Read(2)
+	require.Error(t, err)
+}
+
+func BenchmarkPager_Nop(b *testing.B) {
+	p := OpenNop(4096)
+	defer p.Close()
+
+	_, err := p.Alloc(1)
+	require.NoError(b, err)
+
+	data := make([]byte, 4096)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Write(0, data)
+		p.Read(0)
+	}
+}
+
+func TestPager_WriteAt_NotAllocated(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.NoError(t, p.Reserve(1))
+
+	require.NoError(t, p.WriteAt([]byte{1, 2}, 14))
+	require.ErrorIs(t, p.WriteAt([]byte{1, 2}, 15), ErrNotAllocated)
+	require.NoError(t, p.WriteAtUnsafe([]byte{1, 2}, 15))
+	require.Error(t, p.WriteAtUnsafe([]byte{1, 2}, 31))
+}
+
+func TestPager_ConcurrentRemap(t *testing.T) {
+	filename := "test_remap.bin"
+	os.Remove(filename)
+	defer os.Remove(filename)
+
+	p, err := Open(filename, 64, 0644, WithReadMmap())
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(0, []byte{1}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			data, err := p.Read(0)
+			require.NoError(t, err)
+			require.Equal(t, byte(1), data[0])
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		_, err := p.Alloc(1)
+		require.NoError(t, err)
+	}
+	<-done
+}
+
+func TestPager_Name(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	require.NoError(t, p.Close())
+	require.Equal(t, InMemoryFileName, p.Name())
+}
+
+func TestPager_Struct(t *testing.T) {
+	type record struct {
+		ID    uint32
+		Flags uint16
+		Code  [2]byte
+	}
+
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	id, err := p.Alloc(1)
+	require.NoError(t, err)
+
+	in := record{ID: 1, Flags: 2, Code: [2]byte{'o', 'k'}}
+	require.NoError(t, p.WriteStruct(id, &in))
+
+	data, err := p.Read(id)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 0, 0, 1, 0, 2, 'o', 'k'}, data[:8])
+
+	var out record
+	require.NoError(t, p.ReadStruct(id, &out))
+	require.Equal(t, in, out)
+
+	require.ErrorIs(t, p.WriteStruct(id, [17]byte{}), ErrPageOverflow)
+	require.ErrorIs(t, p.ReadStruct(id, &[17]byte{}), ErrPageOverflow)
+	require.Error(t, p.WriteStruct(id, []string{"x"}))
+}
+
+func TestPager_StrictAlignment(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644, WithStrictAlignment())
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(2)
+	require.NoError(t, err)
+
+	require.NoError(t, p.WriteAt(make([]byte, 16), 16))
+	require.NoError(t, p.ReadAt(make([]byte, 32), 0))
+	require.ErrorIs(t, p.WriteAt(make([]byte, 16), 8), ErrMisaligned)
+	require.ErrorIs(t, p.ReadAt(make([]byte, 8), 0), ErrMisaligned)
+}
+
+func TestOpenCtx(t *testing.T) {
+	p, err := OpenCtx(context.Background(), InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	require.NoError(t, p.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fileName := filepath.Join(t.TempDir(), "pager.bin")
+	_, err = OpenCtx(ctx, fileName, 16, 0644)
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = os.Stat(fileName)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestPager_SetHighWaterMark(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644, WithAllocator(NewFreeListAllocator))
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(4)
+	require.NoError(t, err)
+	require.NoError(t, p.FreePage(1))
+	require.Equal(t, uint64(4), p.HighWaterMark())
+
+	require.NoError(t, p.SetHighWaterMark(2))
+	require.Equal(t, uint64(2), p.Count())
+	require.Equal(t, uint64(4), p.CountPhysical())
+
+	id, err := p.Alloc(1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), id)
+
+	require.NoError(t, p.SetHighWaterMark(6))
+	require.Equal(t, uint64(6), p.CountPhysical())
+	_, err = p.Read(5)
+	require.NoError(t, err)
+}
+
+func TestPager_Files(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	require.Empty(t, p.Files())
+	require.NoError(t, p.Close())
+
+	fileName := filepath.Join(t.TempDir(), "pager.bin")
+	p, err = Open(fileName, 16, 0644)
+	require.NoError(t, err)
+	require.Equal(t, []string{fileName}, p.Files())
+
+	p.Remove()
+	_, err = os.Stat(fileName)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestPager_NewSibling(t *testing.T) {
+	dir := t.TempDir()
+	p, err := Open(filepath.Join(dir, "a.bin"), 24, 0600, WithPageVersions())
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(2)
+	require.NoError(t, err)
+
+	sibling, err := p.NewSibling(filepath.Join(dir, "b.bin"))
+	require.NoError(t, err)
+	defer sibling.Close()
+
+	require.Zero(t, sibling.Count())
+	require.Equal(t, p.PageSize(), sibling.PageSize())
+	stat, err := os.Stat(sibling.Name())
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+
+	_, err = p.NewSibling(sibling.Name())
+	require.ErrorIs(t, err, os.ErrExist)
+}
+
+func TestPager_ModTime(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	_, err = p.ModTime()
+	require.ErrorIs(t, err, errors.ErrUnsupported)
+	require.NoError(t, p.Close())
+
+	fileName := filepath.Join(t.TempDir(), "pager.bin")
+	p, err = Open(fileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	stat, err := os.Stat(fileName)
+	require.NoError(t, err)
+	modTime, err := p.ModTime()
+	require.NoError(t, err)
+	require.Equal(t, stat.ModTime(), modTime)
+}
+
+func TestOpenFile(t *testing.T) {
+	f, err := os.CreateTemp("", "pager")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	p, err := OpenFile(f, 16, WithReadMmap())
+	require.NoError(t, err)
+	require.Equal(t, f.Name(), p.Name())
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(0, []byte{1}))
+
+	data, err := p.Read(0)
+	require.NoError(t, err)
+	require.Equal(t, byte(1), data[0])
+
+	require.NoError(t, p.Close())
+	require.ErrorIs(t, f.Close(), os.ErrClosed)
+}
+
+func TestPager_Overhead(t *testing.T) {
+	p := OpenNop(4096)
+	defer p.Close()
+
+	require.Equal(t, OverheadStats{UsableFraction: 1}, p.Overhead())
+}
+
+func TestPager_PageAlignment(t *testing.T) {
+	p, err := Open(InMemoryFileName, 10, 0644, WithPageAlignment(16))
+	require.NoError(t, err)
+	defer p.Close()
+
+	require.Equal(t, 10, p.PageSize())
+	require.Equal(t, 6, p.Overhead().PageOverhead)
+
+	_, err = p.Alloc(2)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(1, []byte{1}))
+	require.ErrorIs(t, p.Write(0, make([]byte, 11)), ErrPageOverflow)
+
+	data, err := p.Read(1)
+	require.NoError(t, err)
+	require.Len(t, data, 10)
+	require.Equal(t, byte(1), data[0])
+
+	raw, err := p.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, raw, 32)
+	require.Equal(t, byte(1), raw[16])
+}
+
+func TestPager_PageAlignment_Scans(t *testing.T) {
+	p, err := Open(InMemoryFileName, 100, 0644, WithPageAlignment(512))
+	require.NoError(t, err)
+	defer p.Close()
+
+	n := defaultStreamBufferPages + 6
+	_, err = p.Alloc(n)
+	require.NoError(t, err)
+	for id := range uint64(n) {
+		require.NoError(t, p.Write(id, []byte{1}))
+	}
+
+	_, err = p.Checksum()
+	require.NoError(t, err)
+	_, err = p.StateHash()
+	require.NoError(t, err)
+	require.Empty(t, p.DetectGaps())
+	require.NoError(t, p.Warm())
+
+	written, err := p.ReadRangeTo(io.Discard, 0, n)
+	require.NoError(t, err)
+	require.Equal(t, int64(n*100), written)
+
+	visited := 0
+	require.NoError(t, p.ForEachReverse(func(id uint64, data []byte) error {
+		require.Len(t, data, 100)
+		visited++
+		return nil
+	}))
+	require.Equal(t, n, visited)
+}
+
+func TestPager_PhysicalOffset(t *testing.T) {
+	p, err := Open(InMemoryFileName, 10, 0644, WithPageAlignment(16))
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.PhysicalOffset(0)
+	require.Error(t, err)
+
+	_, err = p.Alloc(3)
+	require.NoError(t, err)
+	off, err := p.PhysicalOffset(2)
+	require.NoError(t, err)
+	require.Equal(t, int64(32), off)
+
+	require.NoError(t, p.Write(2, []byte{7}))
+	raw, err := p.ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, byte(7), raw[off])
+}
+
+func TestPager_ReadPhysical(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.NoError(t, p.Reserve(1))
+	require.NoError(t, p.WriteAtUnsafe([]byte{9}, 16))
+
+	_, err = p.Read(1)
+	require.Error(t, err)
+
+	data, err := p.ReadPhysical(1)
+	require.NoError(t, err)
+	require.Equal(t, byte(9), data[0])
+
+	_, err = p.ReadPhysical(2)
+	require.Error(t, err)
+}
+
+func TestPager_Logger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	p, err := Open(InMemoryFileName, 16, 0644, WithLogger(logger))
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(2)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "growing file")
+}
+
+func TestPager_NoLoggerAllocs(t *testing.T) {
+	p := OpenNop(16)
+	defer p.Close()
+
+	// large values, small ones are boxed without allocating
+	_, err := p.grow(100000)
+	require.NoError(t, err)
+	allocs := testing.AllocsPerRun(100, func() {
+		p.grow(1000)
+		p.shrink(1000)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestPager_Trimmed(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	id, err := p.Alloc(1)
+	require.NoError(t, err)
+
+	require.NoError(t, p.WriteTrimmed(id, []byte("hello")))
+	data, err := p.ReadTrimmed(id)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), data)
+
+	require.ErrorIs(t, p.WriteTrimmed(id, make([]byte, 13)), ErrPageOverflow)
+
+	require.NoError(t, p.Write(id, []byte{0xff, 0xff, 0xff, 0xff}))
+	_, err = p.ReadTrimmed(id)
+	require.Error(t, err)
+}
+
+func TestOpenTemp(t *testing.T) {
+	p, err := OpenTemp(t.TempDir(), "pager*.bin", 16, WithReadMmap())
+	require.NoError(t, err)
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	_, err = os.Stat(p.Name())
+	require.NoError(t, err)
+
+	require.NoError(t, p.Close())
+	_, err = os.Stat(p.Name())
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestOptimalPageSize(t *testing.T) {
+	osPageSize := os.Getpagesize()
+
+	require.Equal(t, osPageSize, OptimalPageSize(0))
+	require.Equal(t, osPageSize, OptimalPageSize(1))
+	require.Equal(t, osPageSize, OptimalPageSize(osPageSize))
+	require.Equal(t, 2*osPageSize, OptimalPageSize(osPageSize+1))
+
+	require.True(t, OpenNop(OptimalPageSize(100)).IsAligned())
+	require.False(t, OpenNop(100).IsAligned())
+}
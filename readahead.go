@@ -0,0 +1,82 @@
+package pager
+
+import "sync"
+
+// readAhead prefetches the pages following sequential reads in the
+// background, see WithAutoReadAhead().
+type readAhead struct {
+	pages int
+
+	reqs     chan uint64
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// mu guards the sequence detection state below, Read runs with the
+	// pager lock held shared
+	mu   sync.Mutex
+	last uint64 // id of the last page read plus one, 0 if none
+	next uint64 // first page not prefetched yet
+}
+
+func (p *Pager) startReadAhead(pages int) {
+	ra := &readAhead{
+		pages: pages,
+		reqs:  make(chan uint64, 1),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	p.readAhead = ra
+
+	go func() {
+		defer close(ra.done)
+
+		buf := make([]byte, pages*p.pageSize)
+		for {
+			select {
+			case <-ra.stop:
+				return
+			case from := <-ra.reqs:
+				p.warmChunk(buf, from, pages)
+			}
+		}
+	}()
+}
+
+func (p *Pager) stopReadAhead() {
+	if p.readAhead == nil {
+		return
+	}
+
+	p.readAhead.stopOnce.Do(func() { close(p.readAhead.stop) })
+	<-p.readAhead.done
+}
+
+// noteRead records a read of the page with given id and requests a
+// prefetch of the pages following it if the reads are sequential. The
+// request is dropped if the previous one is still pending.
+func (p *Pager) noteRead(id uint64) {
+	ra := p.readAhead
+	if ra == nil {
+		return
+	}
+
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+
+	sequential := ra.last != 0 && id == ra.last
+	ra.last = id + 1
+	if !sequential {
+		ra.next = 0
+		return
+	} else if id+uint64(ra.pages/2) < ra.next {
+		return
+	}
+
+	from := max(id+1, ra.next)
+	select {
+	case ra.reqs <- from:
+		ra.next = from + uint64(ra.pages)
+	default:
+	}
+}
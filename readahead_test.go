@@ -0,0 +1,31 @@
+package pager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_AutoReadAhead(t *testing.T) {
+	p, err := Open(filepath.Join(t.TempDir(), "pager.bin"), 16, 0644, WithAutoReadAhead(4))
+	require.NoError(t, err)
+
+	_, err = p.Alloc(10)
+	require.NoError(t, err)
+
+	_, err = p.Read(0)
+	require.NoError(t, err)
+	require.Zero(t, p.readAhead.next)
+
+	_, err = p.Read(1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), p.readAhead.next)
+
+	_, err = p.Read(7)
+	require.NoError(t, err)
+	require.Zero(t, p.readAhead.next)
+
+	require.NoError(t, p.Close())
+	require.Nil(t, OpenNop(16, WithAutoReadAhead(4)).readAhead)
+}
@@ -0,0 +1,174 @@
+package pager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// AtomicReplace replaces the whole file of the pager in a crash-safe way.
+// A temporary file is created next to the original one and 'writeFn' is
+// called with a pager for it. If it succeeds, the temporary file is synced
+// and renamed over the original one, which is then reopened by this pager.
+// Either all changes made by 'writeFn' are visible or none of them, even
+// after a crash. The temporary file is removed when 'writeFn' or any other
+// step fails. Requires a file backend, the pager is locked for the whole
+// duration of the replace.
+func (p *Pager) AtomicReplace(writeFn func(tmp *Pager) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	} else if p.readOnly {
+		return ErrReadOnly
+	} else if p.osFile == nil {
+		return errors.New("atomic replace requires a file backend")
+	}
+
+	stat, err := p.osFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(p.fileName)
+	f, err := os.CreateTemp(dir, filepath.Base(p.fileName)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmpName := f.Name()
+
+	if err := p.writeReplacement(f, stat.Mode(), writeFn); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	if err := os.Rename(tmpName, p.fileName); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	syncDir(dir)
+
+	f, err = openFile(p.fileName, openFlag(p.opts), 0)
+	if err != nil {
+		return err
+	}
+	return p.reopen(f)
+}
+
+// Swap replaces the file of the pager with the file named 'newFileName',
+// e.g. a compacted copy, without closing the pager. The new file must have
+// been written with the same page size and options, which is checked as far
+// as possible: there is no header, so only its size has to be a multiple of
+// the page size (ErrTrailingBytes otherwise). The new file is synced and
+// renamed over the current one, so it's no longer available under its old
+// name, and the pager then uses it exactly like AtomicReplace does. The
+// pager is locked exclusively for the whole swap, so no operation sees a
+// mix of both files. Requires a file backend and both files have to be on
+// the same filesystem.
+func (p *Pager) Swap(newFileName string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	} else if p.readOnly {
+		return ErrReadOnly
+	} else if p.osFile == nil {
+		return errors.New("swap requires a file backend")
+	}
+
+	f, err := openFile(newFileName, openFlag(p.opts)&^os.O_CREATE, 0)
+	if err != nil {
+		return err
+	}
+
+	size, err := findSize(f)
+	if err != nil {
+		f.Close()
+		return err
+	} else if size%int64(p.pageSize) != 0 {
+		f.Close()
+		return fmt.Errorf("%w (size=%d, pageSize=%d)", ErrTrailingBytes, size, p.pageSize)
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := os.Rename(newFileName, p.fileName); err != nil {
+		f.Close()
+		return err
+	}
+	syncDir(filepath.Dir(p.fileName))
+	if dir := filepath.Dir(newFileName); dir != filepath.Dir(p.fileName) {
+		syncDir(dir)
+	}
+	return p.reopen(f)
+}
+
+// writeReplacement populates the temporary file using 'writeFn' and makes it
+// durable. The file is always closed.
+func (p *Pager) writeReplacement(f *os.File, mode os.FileMode, writeFn func(tmp *Pager) error) error {
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+
+	tmpOpts := p.opts
+	tmpOpts.scavengeInterval = 0
+
+	tmp, err := newPager(f, f.Name(), p.dataSize, tmpOpts)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	defer tmp.Close()
+
+	if err := writeFn(tmp); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// reopen replaces the underlying file of the pager with 'f' and resets all
+// state derived from it.
+func (p *Pager) reopen(f *os.File) error {
+	size, err := findSize(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := p.preserveRange(0, p.fileSize); err != nil {
+		f.Close()
+		return err
+	}
+
+	p.unmap()
+	p.file.Close()
+
+	p.file = f
+	p.osFile = f
+	if p.windows != nil {
+		p.windows.file = f
+	}
+	p.fileSize = size
+	p.computeCount()
+	p.allocator = p.newAllocator()
+	if p.scavenger != nil {
+		p.scavenger.punched = map[uint64]struct{}{}
+	}
+
+	return p.remap()
+}
+
+// syncDir makes a rename in the directory durable. It's best effort, not all
+// platforms support syncing directories.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
@@ -0,0 +1,103 @@
+package pager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_AtomicReplace(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.bin")
+
+	p, err := Open(filename, 16, 0644, WithReadMmap())
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(0, []byte("old")))
+
+	err = p.AtomicReplace(func(tmp *Pager) error {
+		_, err := tmp.Alloc(1)
+		require.NoError(t, err)
+		return errors.New("failed")
+	})
+	require.EqualError(t, err, "failed")
+
+	err = p.AtomicReplace(func(tmp *Pager) error {
+		if _, err := tmp.Alloc(2); err != nil {
+			return err
+		}
+		return tmp.Write(1, []byte("new"))
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), p.Count())
+
+	data, err := p.Read(1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("new"), data[:3])
+
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestPager_AtomicReplace_PageAlignment(t *testing.T) {
+	p, err := Open(filepath.Join(t.TempDir(), "test.bin"), 100, 0644, WithPageAlignment(512))
+	require.NoError(t, err)
+	defer p.Close()
+
+	err = p.AtomicReplace(func(tmp *Pager) error {
+		require.Equal(t, 100, tmp.PageSize())
+		if _, err := tmp.Alloc(2); err != nil {
+			return err
+		}
+		return tmp.Write(1, []byte("new"))
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), p.Count())
+
+	data, err := p.Read(1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("new"), data[:3])
+}
+
+func TestPager_Swap(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.bin")
+	compacted := filepath.Join(dir, "compacted.bin")
+
+	p, err := Open(filename, 16, 0644, WithReadMmap())
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(3)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(compacted, make([]byte, 20), 0644))
+	require.ErrorIs(t, p.Swap(compacted), ErrTrailingBytes)
+	require.NoError(t, os.Remove(compacted))
+
+	sibling, err := p.NewSibling(compacted)
+	require.NoError(t, err)
+	_, err = sibling.Alloc(1)
+	require.NoError(t, err)
+	require.NoError(t, sibling.Write(0, []byte("new")))
+	require.NoError(t, sibling.Close())
+
+	require.NoError(t, p.Swap(compacted))
+	require.Equal(t, uint64(1), p.Count())
+	require.NoFileExists(t, compacted)
+
+	data, err := p.Read(0)
+	require.NoError(t, err)
+	require.Equal(t, []byte("new"), data[:3])
+
+	require.NoError(t, p.Write(0, []byte("old")))
+	data, err = os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, []byte("old"), data[:3])
+}
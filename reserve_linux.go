@@ -0,0 +1,34 @@
+//go:build linux
+
+package pager
+
+import (
+	"os"
+	"syscall"
+)
+
+// fallocKeepSize is FALLOC_FL_KEEP_SIZE from linux/falloc.h.
+const fallocKeepSize = 0x1
+
+// fallocate allocates disk blocks for the given range of the file without
+// changing its size.
+func fallocate(f *os.File, offset, size int64) error {
+	err := syscall.Fallocate(int(f.Fd()), fallocKeepSize, offset, size)
+	if err == syscall.EOPNOTSUPP || err == syscall.ENOSYS {
+		return errNoFallocate
+	}
+	return err
+}
+
+// fallocPunchHole is FALLOC_FL_PUNCH_HOLE from linux/falloc.h.
+const fallocPunchHole = 0x2
+
+// punchHole releases the disk blocks of the given range of the file. The
+// range reads as zeros afterwards and the file size doesn't change.
+func punchHole(f *os.File, offset, size int64) error {
+	err := syscall.Fallocate(int(f.Fd()), fallocPunchHole|fallocKeepSize, offset, size)
+	if err == syscall.EOPNOTSUPP || err == syscall.ENOSYS {
+		return errNoFallocate
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package pager
+
+import "os"
+
+// fallocate is not available on this platform.
+func fallocate(f *os.File, offset, size int64) error {
+	return errNoFallocate
+}
+
+// punchHole is not available on this platform.
+func punchHole(f *os.File, offset, size int64) error {
+	return errNoFallocate
+}
@@ -0,0 +1,43 @@
+package pager
+
+import (
+	"errors"
+	"time"
+)
+
+// IsRetryable is the default condition deciding which I/O errors are retried
+// when WithRetry is used. It reports whether the error, or any error it
+// wraps, is marked as temporary by a Temporary() bool method returning true.
+// ErrReadOnly is never retryable.
+func IsRetryable(err error) bool {
+	if errors.Is(err, ErrReadOnly) {
+		return false
+	}
+
+	var temp interface{ Temporary() bool }
+	return errors.As(err, &temp) && temp.Temporary()
+}
+
+// retry calls 'op' until it succeeds, fails with an error that isn't
+// retryable or the attempts configured with WithRetry are exhausted.
+func (p *Pager) retry(op func() error) error {
+	isRetryable := p.opts.retryCondition
+	if isRetryable == nil {
+		isRetryable = IsRetryable
+	}
+
+	err := op()
+	for attempt := 1; attempt < p.opts.retryAttempts; attempt++ {
+		if err == nil || errors.Is(err, ErrReadOnly) || !isRetryable(err) {
+			break
+		}
+
+		p.retries.Add(1)
+		p.debug("retrying I/O", "attempt", attempt, "err", err)
+		if p.opts.retryBackoff != nil {
+			time.Sleep(p.opts.retryBackoff(attempt))
+		}
+		err = op()
+	}
+	return err
+}
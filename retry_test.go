@@ -0,0 +1,62 @@
+package pager
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "temporary" }
+func (temporaryError) Temporary() bool { return true }
+
+// flakyFile fails the first 'failures' writes with 'err'.
+type flakyFile struct {
+	*inMemory
+	failures int
+	err      error
+}
+
+func (f *flakyFile) Size() (int64, error) { return int64(len(f.data)), nil }
+
+func (f *flakyFile) WriteAt(b []byte, off int64) (int, error) {
+	if f.failures > 0 {
+		f.failures--
+		return 0, f.err
+	}
+	return f.inMemory.WriteAt(b, off)
+}
+
+func TestPager_Retry(t *testing.T) {
+	var backoffs []int
+	file := &flakyFile{inMemory: &inMemory{}, failures: 2, err: temporaryError{}}
+	p, err := New(file, 16, WithRetry(3, func(attempt int) time.Duration {
+		backoffs = append(backoffs, attempt)
+		return 0
+	}))
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(0, []byte{1}))
+	require.Equal(t, []int{1, 2}, backoffs)
+	require.Equal(t, 2, p.Stats().Retries)
+
+	file.failures = 3
+	require.ErrorIs(t, p.Write(0, []byte{2}), temporaryError{})
+	require.Equal(t, 4, p.Stats().Retries)
+
+	file.failures, file.err = 1, errors.New("permanent")
+	require.Error(t, p.Write(0, []byte{3}))
+	require.Equal(t, 4, p.Stats().Retries)
+}
+
+func TestIsRetryable(t *testing.T) {
+	require.True(t, IsRetryable(temporaryError{}))
+	require.False(t, IsRetryable(errors.New("permanent")))
+	require.False(t, IsRetryable(ErrReadOnly))
+}
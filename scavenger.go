@@ -0,0 +1,153 @@
+package pager
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// scavenger periodically returns the space of free pages to the OS.
+type scavenger struct {
+	interval time.Duration
+	minRun   int
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// pages whose space has already been released
+	punched map[uint64]struct{}
+}
+
+// TrimTail truncates the free pages at the end of the file, if the allocator
+// keeps track of free pages (see FreeTracker). Otherwise it's a no-op.
+func (p *Pager) TrimTail() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	} else if p.readOnly {
+		return ErrReadOnly
+	}
+
+	_, err := p.trimTail()
+	return err
+}
+
+func (p *Pager) trimTail() (int, error) {
+	tracker, ok := p.allocator.(FreeTracker)
+	if !ok {
+		return 0, nil
+	}
+	return tracker.TrimTail()
+}
+
+func (p *Pager) startScavenger(interval time.Duration, minRun int) {
+	p.scavenger = &scavenger{
+		interval: interval,
+		minRun:   minRun,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		punched:  map[uint64]struct{}{},
+	}
+
+	go func() {
+		defer close(p.scavenger.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.scavenger.stop:
+				return
+			case <-ticker.C:
+				p.scavenge()
+			}
+		}
+	}()
+}
+
+func (p *Pager) stopScavenger() {
+	if p.scavenger == nil {
+		return
+	}
+
+	p.scavenger.stopOnce.Do(func() { close(p.scavenger.stop) })
+	<-p.scavenger.done
+}
+
+// scavenge trims the free pages at the end of the file and punches holes
+// into the free runs of at least minRun pages. It's best effort, failures
+// are retried on the next run.
+func (p *Pager) scavenge() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return
+	}
+
+	trimmed, _ := p.trimTail()
+	p.scavenged.Add(int64(trimmed))
+	if trimmed > 0 {
+		if p.logger != nil {
+			p.debug("scavenger trimmed file tail", "pages", trimmed)
+		}
+	}
+
+	tracker, ok := p.allocator.(FreeTracker)
+	if !ok || p.osFile == nil {
+		return
+	}
+
+	free := map[uint64]struct{}{}
+	for _, run := range tracker.FreeRuns() {
+		for id := run.ID; id < run.ID+uint64(run.Len); id++ {
+			free[id] = struct{}{}
+		}
+		if run.Len < p.scavenger.minRun {
+			continue
+		}
+
+		// skip the already punched prefix of the run
+		start := run.ID
+		for ; start < run.ID+uint64(run.Len); start++ {
+			if _, ok := p.scavenger.punched[start]; !ok {
+				break
+			}
+		}
+		n := int(run.ID + uint64(run.Len) - start)
+		if n == 0 {
+			continue
+		}
+
+		if err := p.preserveRange(p.offset(start), int64(n*p.pageSize)); err != nil {
+			continue
+		}
+
+		err := punchHole(p.osFile, p.offset(start), int64(n*p.pageSize))
+		if errors.Is(err, errNoFallocate) {
+			return
+		} else if err != nil {
+			continue
+		}
+
+		if p.logger != nil {
+			p.debug("scavenger punched hole", "first", start, "pages", n)
+		}
+		for id := start; id < start+uint64(n); id++ {
+			p.scavenger.punched[id] = struct{}{}
+		}
+		p.scavenged.Add(int64(n))
+	}
+
+	// forget the pages that have been allocated again
+	for id := range p.scavenger.punched {
+		if _, ok := free[id]; !ok {
+			delete(p.scavenger.punched, id)
+		}
+	}
+}
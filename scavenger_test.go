@@ -0,0 +1,48 @@
+package pager
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_TrimTail(t *testing.T) {
+	for _, newAlloc := range []func(PageSpace) Allocator{NewFreeListAllocator, NewBitmapAllocator} {
+		p := OpenNop(16, WithAllocator(newAlloc))
+
+		_, err := p.Alloc(5)
+		require.NoError(t, err)
+		require.NoError(t, p.FreePages([]uint64{1, 3, 4}))
+
+		require.NoError(t, p.TrimTail())
+		require.Equal(t, uint64(3), p.Count())
+		require.Equal(t, []PageRun{{ID: 1, Len: 1}}, p.allocator.(FreeTracker).FreeRuns())
+		require.NoError(t, p.Close())
+	}
+}
+
+func TestPager_Scavenger(t *testing.T) {
+	filename := "test_scavenger.bin"
+	os.Remove(filename)
+	defer os.Remove(filename)
+
+	p, err := Open(filename, 4096, 0644,
+		WithAllocator(NewFreeListAllocator),
+		WithScavenger(time.Millisecond, 2),
+	)
+	require.NoError(t, err)
+
+	_, err = p.Alloc(8)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(2, []byte{1}))
+	require.NoError(t, p.FreePages([]uint64{1, 2, 3, 6, 7}))
+
+	require.Eventually(t, func() bool {
+		return p.Count() == 6
+	}, time.Second, time.Millisecond)
+	require.NoError(t, p.Close())
+
+	require.GreaterOrEqual(t, p.Stats().Scavenged, 2)
+}
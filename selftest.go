@@ -0,0 +1,80 @@
+package pager
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrSelfTest is returned by SelfTest when the data read back differs from
+// the data written.
+var ErrSelfTest = errors.New("self-test failed")
+
+// SelfTest checks that the backend actually persists data: it allocates a
+// scratch page, fills it with a known pattern, flushes it with Barrier and
+// reads it back bypassing the memory mapping, through a new descriptor for
+// files on disk. The scratch page is freed afterwards, even if the test
+// fails. The pager is locked for the whole test, so no other allocation can
+// get in the way of freeing the page. It returns ErrReadOnly in read-only
+// mode.
+func (p *Pager) SelfTest() (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	} else if p.readOnly {
+		return ErrReadOnly
+	}
+
+	id, err := p.alloc(1)
+	if err != nil {
+		return err
+	}
+	p.allocs.Add(1)
+	defer func() {
+		if freeErr := p.freePages([]uint64{id}); err == nil {
+			err = freeErr
+		}
+	}()
+
+	pattern := make([]byte, p.usableSize())
+	for i := range pattern {
+		pattern[i] = byte(i*31 + 7)
+	}
+	if err := p.writePage(id, pattern); err != nil {
+		return err
+	} else if err := p.sync(); err != nil {
+		return err
+	}
+
+	data, err := p.readBack(id, len(pattern))
+	if err != nil {
+		return err
+	} else if !bytes.Equal(data, pattern) {
+		return fmt.Errorf("%w: page %d doesn't contain the written data", ErrSelfTest, id)
+	}
+	return nil
+}
+
+// readBack reads 'size' bytes of the page with given id directly from the
+// backend, using a new descriptor for files on disk.
+func (p *Pager) readBack(id uint64, size int) ([]byte, error) {
+	var file io.ReaderAt = p.file
+	if p.onDisk {
+		f, err := os.Open(p.fileName)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	data := make([]byte, size)
+	if _, err := file.ReadAt(data, p.offset(id)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,54 @@
+package pager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_SelfTest(t *testing.T) {
+	p, err := Open(filepath.Join(t.TempDir(), "pager.bin"), 16, 0644, WithReadMmap())
+	require.NoError(t, err)
+	require.NoError(t, p.SelfTest())
+	require.Zero(t, p.Count())
+	require.NoError(t, p.Close())
+
+	p, err = Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	require.NoError(t, p.SelfTest())
+	require.NoError(t, p.Close())
+
+	nop := OpenNop(16)
+	require.ErrorIs(t, nop.SelfTest(), ErrSelfTest)
+	require.Zero(t, nop.Count())
+
+	ro, err := Open(InMemoryFileName, 16, 0644, WithReadOnly())
+	require.NoError(t, err)
+	require.ErrorIs(t, ro.SelfTest(), ErrReadOnly)
+}
+
+func TestPager_SelfTest_Concurrent(t *testing.T) {
+	p, err := Open(filepath.Join(t.TempDir(), "pager.bin"), 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	done := make(chan error)
+	go func() {
+		for range 50 {
+			if _, err := p.Alloc(1); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	for range 20 {
+		require.NoError(t, p.SelfTest())
+	}
+	require.NoError(t, <-done)
+
+	require.NoError(t, p.SetReadOnly())
+	require.ErrorIs(t, p.SelfTest(), ErrReadOnly)
+	require.Equal(t, uint64(50), p.Count())
+}
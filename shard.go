@@ -0,0 +1,59 @@
+package pager
+
+import "fmt"
+
+// Shard distributes the pages of this pager across 'n' new files named by
+// 'fileName', which are created with NewSibling and thus get the page size
+// and options of this pager. Page 'id' goes to shard id%n and becomes page
+// id/n there, so shard 's' holds the pages s, s+n, s+2n, ... in order and
+// page 'id' of the pager is page id/n of shard id%n. The raw pages are
+// copied, including page trailers. The pager is locked shared during the
+// copy, so writers wait until it's done. On failure the created shards are
+// removed. The caller owns the returned pagers and has to close them.
+func (p *Pager) Shard(n int, fileName func(shard int) string) ([]*Pager, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("invalid shard count %d", n)
+	}
+
+	shards := make([]*Pager, 0, n)
+	fail := func(err error) ([]*Pager, error) {
+		for _, s := range shards {
+			s.Remove()
+		}
+		return nil, err
+	}
+
+	for i := range n {
+		s, err := p.NewSibling(fileName(i))
+		if err != nil {
+			return fail(err)
+		}
+		shards = append(shards, s)
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for i, s := range shards {
+		if k := (p.count + uint64(n-i-1)) / uint64(n); k > 0 {
+			if _, err := s.Alloc(int(k)); err != nil {
+				return fail(err)
+			}
+		}
+	}
+
+	var writeErr error
+	err := p.scanPages(func(id uint64, page []byte) {
+		if writeErr == nil {
+			s := shards[id%uint64(n)]
+			writeErr = s.WriteAt(page, uint64(s.offset(id/uint64(n))))
+		}
+	})
+	if err == nil {
+		err = writeErr
+	}
+	if err != nil {
+		return fail(err)
+	}
+	return shards, nil
+}
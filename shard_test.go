@@ -0,0 +1,59 @@
+package pager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_Shard(t *testing.T) {
+	dir := t.TempDir()
+	name := func(shard int) string {
+		return filepath.Join(dir, fmt.Sprintf("shard%d.bin", shard))
+	}
+
+	p, err := Open(filepath.Join(dir, "pager.bin"), 16, 0644, WithPageVersions())
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(7)
+	require.NoError(t, err)
+	for id := range uint64(7) {
+		require.NoError(t, p.WriteVersioned(id, []byte{byte(id)}, id+10))
+	}
+
+	_, err = p.Shard(0, name)
+	require.Error(t, err)
+
+	shards, err := p.Shard(3, name)
+	require.NoError(t, err)
+	require.Len(t, shards, 3)
+	for _, s := range shards {
+		defer s.Close()
+	}
+
+	require.Equal(t, uint64(3), shards[0].Count())
+	require.Equal(t, uint64(2), shards[1].Count())
+	require.Equal(t, uint64(2), shards[2].Count())
+	for id := range uint64(7) {
+		data, version, err := shards[id%3].ReadVersioned(id / 3)
+		require.NoError(t, err)
+		require.Equal(t, byte(id), data[0])
+		require.Equal(t, id+10, version)
+	}
+
+	// existing shard files make it fail and remove the shards it created
+	other := func(shard int) string {
+		if shard == 2 {
+			return name(0)
+		}
+		return filepath.Join(dir, fmt.Sprintf("other%d.bin", shard))
+	}
+	_, err = p.Shard(3, other)
+	require.ErrorIs(t, err, os.ErrExist)
+	require.NoFileExists(t, other(0))
+	require.NoFileExists(t, other(1))
+}
@@ -0,0 +1,112 @@
+package pager
+
+import (
+	"errors"
+	"sync"
+)
+
+var _ RandomAccessFile = (*sharedMemoryFile)(nil)
+
+// sharedMemories is the process-wide registry of the buffers opened with
+// OpenSharedMemory, keyed by name.
+var sharedMemories = struct {
+	sync.Mutex
+	byName map[string]*sharedMemory
+}{byName: map[string]*sharedMemory{}}
+
+// sharedMemory is an in-memory file shared by several pagers.
+type sharedMemory struct {
+	mu  sync.RWMutex
+	mem inMemory
+}
+
+// sharedMemoryFile is the handle of one pager to a shared memory buffer.
+// Closing it doesn't affect the buffer or the other handles.
+type sharedMemoryFile struct {
+	shm    *sharedMemory
+	name   string
+	closed bool
+}
+
+// OpenSharedMemory returns a pager for the in-memory buffer registered under
+// given name, creating the buffer if it doesn't exist yet. All pagers opened
+// with the same name in the process see each other's writes; the buffer
+// lives until it's deleted by Remove on any of them.
+//
+// Each call on the buffer is atomic, but the pagers don't coordinate
+// otherwise: the page count of a pager is derived from the buffer size when
+// it's opened, so the pages allocated by another pager afterwards are only
+// visible to pagers opened later. Concurrent writers of the same page must
+// synchronize themselves, just like with a file shared by processes.
+func OpenSharedMemory(name string, pageSize int, opts ...Option) (*Pager, error) {
+	if name == "" {
+		return nil, errors.New("shared memory name must not be empty")
+	}
+
+	sharedMemories.Lock()
+	shm, ok := sharedMemories.byName[name]
+	if !ok {
+		shm = &sharedMemory{}
+		sharedMemories.byName[name] = shm
+	}
+	sharedMemories.Unlock()
+
+	p, err := newPager(&sharedMemoryFile{shm: shm, name: name}, name, pageSize, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	p.sharedName = name
+	return p, nil
+}
+
+// removeSharedMemory deletes the buffer with given name from the registry.
+func removeSharedMemory(name string) {
+	sharedMemories.Lock()
+	defer sharedMemories.Unlock()
+
+	delete(sharedMemories.byName, name)
+}
+
+func (f *sharedMemoryFile) ReadAt(b []byte, off int64) (int, error) {
+	f.shm.mu.RLock()
+	defer f.shm.mu.RUnlock()
+
+	if f.closed {
+		return 0, errors.New("closed file")
+	}
+	return f.shm.mem.ReadAt(b, off)
+}
+
+func (f *sharedMemoryFile) WriteAt(b []byte, off int64) (int, error) {
+	f.shm.mu.Lock()
+	defer f.shm.mu.Unlock()
+
+	if f.closed {
+		return 0, errors.New("closed file")
+	}
+	return f.shm.mem.WriteAt(b, off)
+}
+
+func (f *sharedMemoryFile) Truncate(size int64) error {
+	f.shm.mu.Lock()
+	defer f.shm.mu.Unlock()
+
+	if f.closed {
+		return errors.New("closed file")
+	}
+	return f.shm.mem.Truncate(size)
+}
+
+func (f *sharedMemoryFile) Size() (int64, error) {
+	f.shm.mu.RLock()
+	defer f.shm.mu.RUnlock()
+
+	return f.shm.mem.Size(), nil
+}
+
+func (f *sharedMemoryFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *sharedMemoryFile) Name() string { return f.name }
@@ -0,0 +1,41 @@
+package pager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenSharedMemory(t *testing.T) {
+	producer, err := OpenSharedMemory("queue", 16)
+	require.NoError(t, err)
+	_, err = producer.Alloc(2)
+	require.NoError(t, err)
+	require.NoError(t, producer.Write(1, []byte("hello")))
+
+	consumer, err := OpenSharedMemory("queue", 16)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), consumer.Count())
+
+	data, err := consumer.Read(1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), data[:5])
+
+	require.NoError(t, producer.Write(0, []byte("world")))
+	data, err = consumer.Read(0)
+	require.NoError(t, err)
+	require.Equal(t, []byte("world"), data[:5])
+
+	require.NoError(t, consumer.Close())
+	_, err = producer.Read(0)
+	require.NoError(t, err)
+
+	producer.Remove()
+	fresh, err := OpenSharedMemory("queue", 16)
+	require.NoError(t, err)
+	defer fresh.Remove()
+	require.Zero(t, fresh.Count())
+
+	_, err = OpenSharedMemory("", 16)
+	require.Error(t, err)
+}
@@ -0,0 +1,209 @@
+package pager
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	slottedHeaderSize = 4
+	slotSize          = 4
+)
+
+// ErrInvalidSlot is returned when a slot of a slotted page doesn't exist or
+// refers to a deleted record.
+var ErrInvalidSlot = errors.New("invalid slot")
+
+// SlottedPage packs multiple variable-length records into a single page.
+//
+// The page starts with a 4 byte header holding the number of slots and the
+// offset where the free space after the records begins. Records are stored
+// right after the header, while the slot directory grows backwards from the
+// end of the page. Each slot is 4 bytes holding the offset and length of its
+// record, an offset of 0 marks a deleted record. All values are uint16, so
+// the page size can't exceed 65535 bytes.
+//
+// Slot numbers stay valid until the record is deleted, slots of deleted
+// records are reused by later inserts. Every modification is written to the
+// pager immediately.
+type SlottedPage struct {
+	pager *Pager
+	id    uint64
+	data  []byte
+}
+
+// OpenSlottedPage reads the page with given id and returns a slotted page
+// view of it. A zeroed page is treated as an empty slotted page. The page
+// must have room for the header and at least one slot.
+func OpenSlottedPage(p *Pager, id uint64) (*SlottedPage, error) {
+	if p.PageSize() > math.MaxUint16 {
+		return nil, fmt.Errorf("page size %d is too large for a slotted page", p.PageSize())
+	} else if p.PageSize() < slottedHeaderSize+slotSize {
+		return nil, fmt.Errorf("page size %d is too small for a slotted page", p.PageSize())
+	}
+
+	data, err := p.Read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	sp := &SlottedPage{pager: p, id: id, data: data}
+	if sp.freeStart() > sp.dirStart() {
+		return nil, fmt.Errorf("page %d is not a valid slotted page", id)
+	}
+	return sp, nil
+}
+
+// InsertRecord stores the record in the page and returns its slot. The free
+// space of the page is compacted if needed. Returns an error wrapping
+// ErrPageOverflow if the record doesn't fit.
+func (sp *SlottedPage) InsertRecord(rec []byte) (int, error) {
+	slot, need := -1, len(rec)
+	for i := 0; i < sp.slotCount(); i++ {
+		if off, _ := sp.slot(i); off == 0 {
+			slot = i
+			break
+		}
+	}
+	if slot == -1 {
+		need += slotSize
+	}
+
+	if sp.freeStart()+need > sp.dirStart() {
+		if need > sp.FreeSpace() {
+			return 0, fmt.Errorf("%w (record=%d, free=%d)", ErrPageOverflow, len(rec), sp.FreeSpace())
+		}
+		sp.compact()
+	}
+
+	if slot == -1 {
+		slot = sp.slotCount()
+		sp.setSlotCount(slot + 1)
+	}
+
+	off := sp.freeStart()
+	copy(sp.data[off:], rec)
+	sp.setSlot(slot, off, len(rec))
+	sp.setFreeStart(off + len(rec))
+
+	return slot, sp.flush()
+}
+
+// GetRecord returns a copy of the record stored in given slot.
+func (sp *SlottedPage) GetRecord(slot int) ([]byte, error) {
+	off, length, err := sp.liveSlot(slot)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := make([]byte, length)
+	copy(rec, sp.data[off:])
+	return rec, nil
+}
+
+// DeleteRecord removes the record stored in given slot. The space used by
+// the record is reclaimed by the next compaction.
+func (sp *SlottedPage) DeleteRecord(slot int) error {
+	if _, _, err := sp.liveSlot(slot); err != nil {
+		return err
+	}
+	sp.setSlot(slot, 0, 0)
+
+	n := sp.slotCount()
+	for n > 0 {
+		if off, _ := sp.slot(n - 1); off != 0 {
+			break
+		}
+		n--
+	}
+	sp.setSlotCount(n)
+
+	return sp.flush()
+}
+
+// FreeSpace returns the number of bytes available for new records and slots
+// after compaction.
+func (sp *SlottedPage) FreeSpace() int {
+	used := 0
+	for i := 0; i < sp.slotCount(); i++ {
+		_, length := sp.slot(i)
+		used += length
+	}
+	return sp.dirStart() - slottedHeaderSize - used
+}
+
+// compact moves all live records to the beginning of the page so that the
+// free space becomes contiguous.
+func (sp *SlottedPage) compact() {
+	n := sp.slotCount()
+	records := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		if off, length := sp.slot(i); off != 0 {
+			records[i] = append([]byte{}, sp.data[off:off+length]...)
+		}
+	}
+
+	off := slottedHeaderSize
+	for i, rec := range records {
+		if rec == nil {
+			continue
+		}
+		copy(sp.data[off:], rec)
+		sp.setSlot(i, off, len(rec))
+		off += len(rec)
+	}
+	sp.setFreeStart(off)
+}
+
+func (sp *SlottedPage) liveSlot(slot int) (off, length int, err error) {
+	if slot < 0 || slot >= sp.slotCount() {
+		return 0, 0, fmt.Errorf("%w: %d (slots=%d)", ErrInvalidSlot, slot, sp.slotCount())
+	}
+
+	off, length = sp.slot(slot)
+	if off == 0 {
+		return 0, 0, fmt.Errorf("%w: %d is deleted", ErrInvalidSlot, slot)
+	} else if off < slottedHeaderSize || off+length > sp.dirStart() {
+		return 0, 0, fmt.Errorf("%w: %d is corrupt (offset=%d, length=%d)", ErrInvalidSlot, slot, off, length)
+	}
+	return off, length, nil
+}
+
+func (sp *SlottedPage) flush() error {
+	return sp.pager.Write(sp.id, sp.data)
+}
+
+func (sp *SlottedPage) slotCount() int {
+	return int(bin.Uint16(sp.data[0:]))
+}
+
+func (sp *SlottedPage) setSlotCount(n int) {
+	bin.PutUint16(sp.data[0:], uint16(n))
+}
+
+func (sp *SlottedPage) freeStart() int {
+	if off := int(bin.Uint16(sp.data[2:])); off != 0 {
+		return off
+	}
+	return slottedHeaderSize
+}
+
+func (sp *SlottedPage) setFreeStart(off int) {
+	bin.PutUint16(sp.data[2:], uint16(off))
+}
+
+func (sp *SlottedPage) dirStart() int {
+	return len(sp.data) - sp.slotCount()*slotSize
+}
+
+func (sp *SlottedPage) slot(i int) (off, length int) {
+	pos := len(sp.data) - (i+1)*slotSize
+	return int(bin.Uint16(sp.data[pos:])), int(bin.Uint16(sp.data[pos+2:]))
+}
+
+func (sp *SlottedPage) setSlot(i, off, length int) {
+	pos := len(sp.data) - (i+1)*slotSize
+	bin.PutUint16(sp.data[pos:], uint16(off))
+	bin.PutUint16(sp.data[pos+2:], uint16(length))
+}
@@ -0,0 +1,78 @@
+package pager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlottedPage(t *testing.T) {
+	p, err := Open(InMemoryFileName, 64, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	id, err := p.Alloc(1)
+	require.NoError(t, err)
+
+	sp, err := OpenSlottedPage(p, id)
+	require.NoError(t, err)
+	require.Equal(t, 60, sp.FreeSpace())
+
+	a, err := sp.InsertRecord([]byte("aaaaaaaaaaaaaaaaaaaa"))
+	require.NoError(t, err)
+	b, err := sp.InsertRecord([]byte("bbbbbbbbbbbbbbbbbbbb"))
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1}, []int{a, b})
+
+	_, err = sp.InsertRecord(make([]byte, 20))
+	require.ErrorIs(t, err, ErrPageOverflow)
+
+	require.NoError(t, sp.DeleteRecord(a))
+	_, err = sp.GetRecord(a)
+	require.ErrorIs(t, err, ErrInvalidSlot)
+
+	// reuses the deleted slot and needs compaction to fit
+	c, err := sp.InsertRecord([]byte("cccccccccccccccccccccccc"))
+	require.NoError(t, err)
+	require.Equal(t, a, c)
+
+	sp, err = OpenSlottedPage(p, id)
+	require.NoError(t, err)
+
+	rec, err := sp.GetRecord(b)
+	require.NoError(t, err)
+	require.Equal(t, []byte("bbbbbbbbbbbbbbbbbbbb"), rec)
+
+	rec, err = sp.GetRecord(c)
+	require.NoError(t, err)
+	require.Equal(t, []byte("cccccccccccccccccccccccc"), rec)
+}
+
+func TestSlottedPage_Invalid(t *testing.T) {
+	small := OpenNop(2)
+	_, err := small.Alloc(1)
+	require.NoError(t, err)
+	_, err = OpenSlottedPage(small, 0)
+	require.Error(t, err)
+
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+
+	// one slot whose record reaches beyond the page
+	page := make([]byte, 16)
+	bin.PutUint16(page[0:], 1)
+	bin.PutUint16(page[2:], 8)
+	bin.PutUint16(page[12:], 4)
+	bin.PutUint16(page[14:], 100)
+	require.NoError(t, p.Write(0, page))
+
+	sp, err := OpenSlottedPage(p, 0)
+	require.NoError(t, err)
+	_, err = sp.GetRecord(0)
+	require.ErrorIs(t, err, ErrInvalidSlot)
+	require.ErrorIs(t, sp.DeleteRecord(0), ErrInvalidSlot)
+}
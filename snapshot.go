@@ -0,0 +1,103 @@
+package pager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// ErrSnapshotReleased is returned when reading from a released snapshot.
+var ErrSnapshotReleased = errors.New("snapshot released")
+
+// Snapshot is a consistent point-in-time view of the pages of a pager, see
+// Pager.Snapshot.
+type Snapshot struct {
+	p     *Pager
+	count uint64
+
+	// original contents of the pages modified after the snapshot was taken
+	pages map[uint64][]byte
+}
+
+// Snapshot captures the current state of the pager. Writers can continue
+// while the snapshot is in use: before a page is modified (or truncated) for
+// the first time after the snapshot was taken, its original content is
+// copied and kept in memory by the snapshot. The preserved copies are freed
+// by Release, which must be called once the snapshot is no longer needed.
+func (p *Pager) Snapshot() (*Snapshot, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return nil, os.ErrClosed
+	}
+
+	snap := &Snapshot{p: p, count: p.count, pages: map[uint64][]byte{}}
+	p.snapshots = append(p.snapshots, snap)
+	return snap, nil
+}
+
+// Count returns the number of pages at the time the snapshot was taken.
+func (s *Snapshot) Count() uint64 { return s.count }
+
+// Read returns the content the page with given id had when the snapshot was
+// taken.
+func (s *Snapshot) Read(id uint64) ([]byte, error) {
+	s.p.mu.RLock()
+	defer s.p.mu.RUnlock()
+
+	if s.pages == nil {
+		return nil, ErrSnapshotReleased
+	} else if id >= s.count {
+		return nil, fmt.Errorf("invalid page id=%d (max=%d)", id, s.count-1)
+	}
+
+	size := s.p.usableSize()
+	if data, ok := s.pages[id]; ok {
+		return slices.Clone(data[:size]), nil
+	} else if s.p.file == nil {
+		return nil, os.ErrClosed
+	}
+	return s.p.readPage(id)
+}
+
+// Release frees the pages preserved by the snapshot and stops preserving new
+// ones. The snapshot can't be used afterwards.
+func (s *Snapshot) Release() {
+	s.p.mu.Lock()
+	defer s.p.mu.Unlock()
+
+	s.p.snapshots = slices.DeleteFunc(s.p.snapshots, func(snap *Snapshot) bool {
+		return snap == s
+	})
+	s.pages = nil
+}
+
+// preserveRange saves the original content of the pages overlapping the
+// given byte range into the active snapshots that don't have them yet.
+func (p *Pager) preserveRange(off, size int64) error {
+	if len(p.snapshots) == 0 || size <= 0 {
+		return nil
+	}
+
+	first := uint64(off / int64(p.pageSize))
+	last := uint64((off + size - 1) / int64(p.pageSize))
+	for id := first; id <= last; id++ {
+		var data []byte
+		for _, snap := range p.snapshots {
+			if _, ok := snap.pages[id]; ok || id >= snap.count {
+				continue
+			}
+
+			if data == nil {
+				var err error
+				if data, err = p.loadPage(id); err != nil {
+					return err
+				}
+			}
+			snap.pages[id] = data
+		}
+	}
+	return nil
+}
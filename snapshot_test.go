@@ -0,0 +1,46 @@
+package pager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_Snapshot(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(3)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(0, []byte("zero")))
+	require.NoError(t, p.Write(2, []byte("two")))
+
+	snap, err := p.Snapshot()
+	require.NoError(t, err)
+
+	require.NoError(t, p.Write(0, []byte("ZERO")))
+	require.NoError(t, p.Free(1))
+	_, err = p.Alloc(2)
+	require.NoError(t, err)
+
+	data, err := snap.Read(0)
+	require.NoError(t, err)
+	require.Equal(t, []byte("zero"), data[:4])
+
+	data, err = snap.Read(2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("two"), data[:3])
+
+	_, err = snap.Read(3)
+	require.Error(t, err)
+	require.Len(t, snap.pages, 2)
+
+	snap.Release()
+	_, err = snap.Read(0)
+	require.ErrorIs(t, err, ErrSnapshotReleased)
+
+	data, err = p.Read(0)
+	require.NoError(t, err)
+	require.Equal(t, []byte("ZERO"), data[:4])
+}
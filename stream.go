@@ -0,0 +1,250 @@
+package pager
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+// defaultStreamBufferPages is the number of pages read at once by
+// ReadRangeTo and the other methods scanning many pages, unless changed by
+// WithStreamBufferSize().
+const defaultStreamBufferPages = 64
+
+// defaultReadAllLimit is the maximum number of bytes ReadAll loads unless
+// changed by WithReadAllLimit().
+const defaultReadAllLimit = 64 << 20
+
+// ErrTooLarge is returned by ReadAll when the file exceeds the limit set by
+// WithReadAllLimit().
+var ErrTooLarge = errors.New("file too large")
+
+// ReadAll returns the raw contents of all allocated pages, including page
+// trailers, read with a single ReadAt call. It's meant for small files and
+// fails with ErrTooLarge for files above the limit (64 MiB by default, see
+// WithReadAllLimit). The data is always read from the file or its memory
+// mapping, never from a cache.
+func (p *Pager) ReadAll() ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	limit := int64(defaultReadAllLimit)
+	if p.opts.readAllLimit > 0 {
+		limit = p.opts.readAllLimit
+	}
+
+	if p.file == nil {
+		return nil, os.ErrClosed
+	} else if size := p.offset(p.count); size > limit {
+		return nil, fmt.Errorf("%w (size=%d, limit=%d)", ErrTooLarge, size, limit)
+	}
+
+	buf := make([]byte, p.offset(p.count))
+	if err := p.readPages(buf, 0); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// ReadRangeTo writes the data of 'n' pages starting from 'startID' into 'w'
+// and returns the number of bytes written. The pages are read in chunks into
+// a fixed-size buffer (see WithStreamBufferSize), so the range doesn't need
+// to fit into memory. The pager is not locked while writing to 'w'.
+func (p *Pager) ReadRangeTo(w io.Writer, startID uint64, n int) (int64, error) {
+	if n <= 0 {
+		return 0, nil
+	} else if count := p.Count(); startID+uint64(n) > count {
+		return 0, fmt.Errorf("invalid page range id=%d n=%d (count=%d)", startID, n, count)
+	}
+
+	chunkPages := p.streamPages()
+	buf := p.buffers.get(chunkPages)
+	defer p.buffers.put(buf)
+	usable := p.usableSize()
+
+	var written int64
+	for id, end := startID, startID+uint64(n); id < end; {
+		k := min(uint64(chunkPages), end-id)
+		chunk := buf[:k*uint64(p.pageSize)]
+		if err := p.readPagesLocked(chunk, id); err != nil {
+			return written, err
+		}
+
+		for off := 0; off < len(chunk); off += p.pageSize {
+			m, err := w.Write(chunk[off : off+usable])
+			written += int64(m)
+			if err != nil {
+				return written, err
+			}
+		}
+		id += k
+	}
+	return written, nil
+}
+
+// ForEachReverse calls 'fn' with the data of every allocated page, from the
+// last page down to page 0, and stops at the first error returned by 'fn'.
+// The pages are read backwards in chunks into a single reused buffer (see
+// WithStreamBufferSize), so 'data' is only valid during the call. Pages
+// allocated after the call started are not visited. The pager is not locked
+// while 'fn' runs.
+func (p *Pager) ForEachReverse(fn func(id uint64, data []byte) error) error {
+	chunkPages := p.streamPages()
+	buf := p.buffers.get(chunkPages)
+	defer p.buffers.put(buf)
+	usable := p.usableSize()
+
+	for end := p.Count(); end > 0; {
+		k := min(uint64(chunkPages), end)
+		start := end - k
+		chunk := buf[:k*uint64(p.pageSize)]
+		if err := p.readPagesLocked(chunk, start); err != nil {
+			return err
+		}
+
+		for i := k; i > 0; i-- {
+			off := int(i-1) * p.pageSize
+			if err := fn(start+i-1, chunk[off:off+usable:off+usable]); err != nil {
+				return err
+			}
+		}
+		end = start
+	}
+	return nil
+}
+
+// Seeker returns an io.ReadWriteSeeker over the raw bytes of the whole file,
+// with its own cursor, so several seekers can be used independently. It
+// bypasses the page semantics: reads and writes go through ReadAt and
+// WriteAtUnsafe, see across page boundaries and page trailers, and are
+// bounded by the current file size. Writes can't grow the file.
+func (p *Pager) Seeker() io.ReadWriteSeeker { return &seeker{p: p} }
+
+type seeker struct {
+	p   *Pager
+	pos int64
+}
+
+func (s *seeker) Read(b []byte) (int, error) {
+	size := s.p.size()
+	if s.pos >= size {
+		return 0, io.EOF
+	}
+
+	n := int(min(int64(len(b)), size-s.pos))
+	if err := s.p.ReadAt(b[:n], uint64(s.pos)); err != nil {
+		return 0, err
+	}
+	s.pos += int64(n)
+	return n, nil
+}
+
+func (s *seeker) Write(b []byte) (int, error) {
+	if err := s.p.WriteAtUnsafe(b, uint64(s.pos)); err != nil {
+		return 0, err
+	}
+	s.pos += int64(len(b))
+	return len(b), nil
+}
+
+func (s *seeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += s.pos
+	case io.SeekEnd:
+		offset += s.p.size()
+	case io.SeekStart:
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+
+	if offset < 0 {
+		return 0, fmt.Errorf("negative position %d", offset)
+	}
+	s.pos = offset
+	return offset, nil
+}
+
+// size returns the current size of the file.
+func (p *Pager) size() int64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.fileSize
+}
+
+// ReadMany reads the pages with given ids and returns their data keyed by
+// id. Duplicate ids are read once and the ids are sorted, so that runs of
+// adjacent pages are read with a single call. On error the returned map
+// holds the pages read before the failing run, which is the most useful
+// partial result for sequential consumers; it's never nil.
+func (p *Pager) ReadMany(ids []uint64) (map[uint64][]byte, error) {
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	pages := make(map[uint64][]byte, len(sorted))
+	usable := p.usableSize()
+	for len(sorted) > 0 {
+		n := 1
+		for n < len(sorted) && sorted[n] == sorted[0]+uint64(n) {
+			n++
+		}
+
+		buf := make([]byte, n*p.pageSize)
+		if err := p.readPages(buf, sorted[0]); err != nil {
+			return pages, err
+		}
+		for i, id := range sorted[:n] {
+			off := i * p.pageSize
+			pages[id] = buf[off : off+usable : off+usable]
+		}
+		sorted = sorted[n:]
+	}
+	return pages, nil
+}
+
+// streamPages returns the number of pages read at once by the methods
+// scanning many pages.
+func (p *Pager) streamPages() int {
+	if p.opts.streamPages > 0 {
+		return p.opts.streamPages
+	}
+	return defaultStreamBufferPages
+}
+
+// readPagesLocked is readPages holding the pager lock.
+func (p *Pager) readPagesLocked(dst []byte, id uint64) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.readPages(dst, id)
+}
+
+// readPages reads len(dst)/pageSize physical pages starting from 'id' into
+// 'dst'.
+func (p *Pager) readPages(dst []byte, id uint64) error {
+	n := uint64(len(dst) / p.pageSize)
+	if id+n > p.count {
+		return fmt.Errorf("invalid page range id=%d n=%d (count=%d)", id, n, p.count)
+	} else if p.file == nil {
+		return os.ErrClosed
+	}
+
+	if !p.copyMapped(dst, p.offset(id)) {
+		if m, err := p.readAt(dst, p.offset(id)); m < len(dst) {
+			return shortRead(len(dst), m, err)
+		}
+	}
+
+	p.reads.Add(int64(n))
+	for i := id; i < id+n; i++ {
+		p.trackAccess(i, false)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package pager
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_ReadRangeTo(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644, WithPageVersions())
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(40)
+	require.NoError(t, err)
+	for i := uint64(0); i < 40; i++ {
+		require.NoError(t, p.WriteVersioned(i, bytes.Repeat([]byte{byte(i)}, 8), 1))
+	}
+
+	buf := &bytes.Buffer{}
+	n, err := p.ReadRangeTo(buf, 3, 35)
+	require.NoError(t, err)
+	require.Equal(t, int64(35*8), n)
+	require.Equal(t, bytes.Repeat([]byte{3}, 8), buf.Bytes()[:8])
+	require.Equal(t, bytes.Repeat([]byte{37}, 8), buf.Bytes()[34*8:])
+
+	_, err = p.ReadRangeTo(buf, 30, 11)
+	require.Error(t, err)
+}
+
+func TestPager_ReadMany(t *testing.T) {
+	f := &readCountFile{}
+	p, err := New(f, 16)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(10)
+	require.NoError(t, err)
+	for id := range uint64(10) {
+		require.NoError(t, p.Write(id, []byte{byte(id)}))
+	}
+
+	pages, err := p.ReadMany([]uint64{7, 2, 3, 2, 8})
+	require.NoError(t, err)
+	require.Len(t, pages, 4)
+	for id, data := range pages {
+		require.Len(t, data, 16)
+		require.Equal(t, byte(id), data[0])
+	}
+	require.Equal(t, 2, f.reads)
+
+	pages, err = p.ReadMany([]uint64{1, 12})
+	require.Error(t, err)
+	require.Len(t, pages, 1)
+}
+
+func TestPager_ForEachReverse(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644, WithStreamBufferSize(3))
+	require.NoError(t, err)
+	defer p.Close()
+
+	require.NoError(t, p.ForEachReverse(func(uint64, []byte) error {
+		t.Fatal("called for an empty pager")
+		return nil
+	}))
+
+	_, err = p.Alloc(7)
+	require.NoError(t, err)
+	for id := range uint64(7) {
+		require.NoError(t, p.Write(id, []byte{byte(id)}))
+	}
+
+	var ids []uint64
+	require.NoError(t, p.ForEachReverse(func(id uint64, data []byte) error {
+		require.Len(t, data, 16)
+		require.Equal(t, byte(id), data[0])
+		ids = append(ids, id)
+		return nil
+	}))
+	require.Equal(t, []uint64{6, 5, 4, 3, 2, 1, 0}, ids)
+
+	stop := errors.New("stop")
+	ids = nil
+	err = p.ForEachReverse(func(id uint64, _ []byte) error {
+		ids = append(ids, id)
+		if id == 4 {
+			return stop
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, stop)
+	require.Equal(t, []uint64{6, 5, 4}, ids)
+}
+
+func TestPager_Seeker(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(2)
+	require.NoError(t, err)
+
+	w, r := p.Seeker(), p.Seeker()
+	_, err = w.Seek(14, io.SeekStart)
+	require.NoError(t, err)
+	n, err := w.Write([]byte("span"))
+	require.NoError(t, err)
+	require.Equal(t, 4, n)
+
+	pos, err := r.Seek(-18, io.SeekEnd)
+	require.NoError(t, err)
+	require.Equal(t, int64(14), pos)
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Len(t, data, 18)
+	require.Equal(t, []byte("span"), data[:4])
+
+	_, err = w.Seek(30, io.SeekStart)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("long"))
+	require.Error(t, err)
+	_, err = w.Seek(-1, io.SeekStart)
+	require.Error(t, err)
+}
+
+// readCountFile counts the ReadAt calls.
+type readCountFile struct {
+	sizerFile
+	reads int
+}
+
+func (f *readCountFile) ReadAt(b []byte, off int64) (int, error) {
+	f.reads++
+	return f.sizerFile.ReadAt(b, off)
+}
+
+func TestPager_StreamBufferSize(t *testing.T) {
+	f := &readCountFile{}
+	p, err := New(f, 16, WithStreamBufferSize(4))
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(10)
+	require.NoError(t, err)
+
+	n, err := p.ReadRangeTo(&bytes.Buffer{}, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(160), n)
+	require.Equal(t, 3, f.reads)
+
+	require.Equal(t, 1, OpenNop(16, WithStreamBufferSize(0)).streamPages())
+	require.Equal(t, defaultStreamBufferPages, OpenNop(16).streamPages())
+}
+
+func TestPager_ReadAll(t *testing.T) {
+	p, err := Open(InMemoryFileName, 16, 0644, WithReadAllLimit(48))
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(3)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(2, []byte{7}))
+
+	data, err := p.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, data, 48)
+	require.Equal(t, byte(7), data[32])
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	_, err = p.ReadAll()
+	require.ErrorIs(t, err, ErrTooLarge)
+}
@@ -0,0 +1,114 @@
+package pager
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// syncer is implemented by backends that can flush written data to durable
+// storage, like os.File.
+type syncer interface {
+	Sync() error
+}
+
+// Barrier makes all writes completed before the call durable before any
+// write issued after it can start. It fsyncs the underlying file while
+// holding the pager lock exclusively, so writes issued concurrently wait
+// until the barrier is done. Writes that were not completed when Barrier was
+// called are ordered after it. It's a no-op for in-memory backends and other
+// backends that don't implement Sync.
+func (p *Pager) Barrier() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	}
+	return p.sync()
+}
+
+// Sync flushes all completed writes to durable storage. With
+// WithGroupCommit() concurrent calls are coalesced: the first caller waits
+// for the configured window and issues a single fsync on behalf of every
+// call made meanwhile. A call only returns after an fsync started after the
+// call itself, so all writes completed before Sync are covered. Unlike
+// Barrier, writes are only blocked while the fsync runs. It's a no-op for
+// backends that don't implement Sync.
+func (p *Pager) Sync() error {
+	g := p.group
+	if g == nil {
+		return p.syncShared()
+	}
+
+	g.mu.Lock()
+	round, leader := g.next, false
+	if round == nil {
+		round, leader = &syncRound{done: make(chan struct{})}, true
+		g.next = round
+	}
+	g.mu.Unlock()
+
+	if leader {
+		time.Sleep(g.window)
+
+		g.mu.Lock()
+		g.next = nil
+		g.mu.Unlock()
+
+		round.err = p.syncShared()
+		close(round.done)
+	}
+
+	<-round.done
+	return round.err
+}
+
+// groupCommit coalesces concurrent Sync calls, see WithGroupCommit().
+type groupCommit struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	next *syncRound // round accepting new callers, nil if none
+}
+
+// syncRound is a single fsync shared by a group of Sync calls.
+type syncRound struct {
+	done chan struct{}
+	err  error
+}
+
+// syncShared fsyncs the file holding the pager lock shared, which keeps
+// writes out while in-flight reads continue. Concurrent fsyncs are
+// serialized by syncMu.
+func (p *Pager) syncShared() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	}
+	return p.sync()
+}
+
+func (p *Pager) sync() error {
+	s, ok := p.file.(syncer)
+	if !ok {
+		return nil
+	}
+
+	p.syncMu.Lock()
+	defer p.syncMu.Unlock()
+
+	if err := p.msyncDirty(); err != nil {
+		return err
+	}
+
+	p.fsyncs.Add(1)
+	p.applyDeadline()
+	if err := s.Sync(); err != nil {
+		return err
+	}
+	p.dirty.reset()
+	return nil
+}
@@ -0,0 +1,54 @@
+package pager
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_Barrier(t *testing.T) {
+	mem, err := Open(InMemoryFileName, 16, 0644)
+	require.NoError(t, err)
+	require.NoError(t, mem.Barrier())
+	require.NoError(t, mem.Close())
+	require.ErrorIs(t, mem.Barrier(), os.ErrClosed)
+
+	p, err := Open(filepath.Join(t.TempDir(), "test.bin"), 16, 0644)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+	require.NoError(t, p.Write(0, []byte{1}))
+	require.NoError(t, p.Barrier())
+}
+
+func TestPager_SyncGroupCommit(t *testing.T) {
+	p, err := Open(filepath.Join(t.TempDir(), "test.bin"), 16, 0644,
+		WithGroupCommit(50*time.Millisecond),
+	)
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, p.Sync())
+		}()
+	}
+	wg.Wait()
+	require.Less(t, p.Stats().Fsyncs, 10)
+
+	require.NoError(t, p.Sync())
+	require.NoError(t, p.Close())
+	require.ErrorIs(t, p.Sync(), os.ErrClosed)
+}
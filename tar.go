@@ -0,0 +1,121 @@
+package pager
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// tarPageSizeKey and tarPageAlignKey are the PAX records used to store the
+// page size and the page alignment (see WithPageAlignment) of an exported
+// pager.
+const (
+	tarPageSizeKey  = "PAGER.pagesize"
+	tarPageAlignKey = "PAGER.pagealign"
+)
+
+// ExportTar writes all allocated pages of the pager into a tar archive as a
+// single file entry recording the page size and alignment of the pager.
+// Writes are blocked until the export is done, so the archive is a
+// consistent snapshot.
+func (p *Pager) ExportTar(w io.Writer) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.file == nil {
+		return os.ErrClosed
+	}
+
+	size := p.offset(p.count)
+	tw := tar.NewWriter(w)
+
+	records := map[string]string{tarPageSizeKey: strconv.Itoa(p.dataSize)}
+	if p.opts.pageAlign > 0 {
+		records[tarPageAlignKey] = strconv.Itoa(p.opts.pageAlign)
+	}
+
+	err := tw.WriteHeader(&tar.Header{
+		Typeflag:   tar.TypeReg,
+		Name:       filepath.Base(p.fileName),
+		Size:       size,
+		Mode:       0644,
+		Format:     tar.FormatPAX,
+		PAXRecords: records,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(tw, io.NewSectionReader(readerAtFunc(p.readAt), 0, size)); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// ImportTar restores a pager exported with ExportTar into the named file and
+// returns a pager opened with the recorded page size and alignment, which
+// take precedence over a WithPageAlignment() in 'opts'. The target file must
+// be empty or not exist.
+func ImportTar(r io.Reader, fileName string, mode os.FileMode, opts ...Option) (*Pager, error) {
+	tr := tar.NewReader(r)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize, err := strconv.Atoi(hdr.PAXRecords[tarPageSizeKey])
+	if err != nil || pageSize <= 0 {
+		return nil, errors.New("archive doesn't record a valid page size")
+	}
+	if rec, ok := hdr.PAXRecords[tarPageAlignKey]; ok {
+		align, err := strconv.Atoi(rec)
+		if err != nil || align <= 0 {
+			return nil, errors.New("archive doesn't record a valid page alignment")
+		}
+		opts = append(opts[:len(opts):len(opts)], WithPageAlignment(align))
+	}
+
+	p, err := Open(fileName, pageSize, mode, opts...)
+	if err != nil {
+		return nil, err
+	} else if p.fileSize != 0 {
+		p.Close()
+		return nil, fmt.Errorf("target file '%s' is not empty", fileName)
+	} else if hdr.Size%int64(p.pageSize) != 0 {
+		p.Close()
+		return nil, fmt.Errorf("archived size %d is not a multiple of page size %d", hdr.Size, p.pageSize)
+	}
+
+	if err := p.importPages(tr, int(hdr.Size/int64(p.pageSize))); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Pager) importPages(r io.Reader, n int) error {
+	if n == 0 {
+		return nil
+	}
+
+	id, err := p.Alloc(n)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, p.pageSize)
+	for i := 0; i < n; i++ {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+		if err := p.WriteAt(buf, uint64(p.offset(id+uint64(i)))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package pager
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_ExportTar(t *testing.T) {
+	src, err := Open(InMemoryFileName, 32, 0644)
+	require.NoError(t, err)
+	defer src.Close()
+
+	_, err = src.Alloc(3)
+	require.NoError(t, err)
+	require.NoError(t, src.Write(2, []byte("hello")))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, src.ExportTar(buf))
+
+	dst, err := ImportTar(buf, InMemoryFileName, 0644)
+	require.NoError(t, err)
+	defer dst.Close()
+
+	require.Equal(t, 32, dst.PageSize())
+	require.Equal(t, uint64(3), dst.Count())
+
+	data, err := dst.Read(2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), data[:5])
+}
+
+func TestPager_ExportTar_Versions(t *testing.T) {
+	src, err := Open(InMemoryFileName, 64, 0644, WithPageVersions())
+	require.NoError(t, err)
+	defer src.Close()
+
+	_, err = src.Alloc(2)
+	require.NoError(t, err)
+	require.NoError(t, src.WriteVersioned(1, []byte("hello"), 7))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, src.ExportTar(buf))
+
+	dst, err := ImportTar(buf, InMemoryFileName, 0644, WithPageVersions())
+	require.NoError(t, err)
+	defer dst.Close()
+
+	data, version, err := dst.ReadVersioned(1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), data[:5])
+	require.Equal(t, uint64(7), version)
+}
+
+func TestPager_ExportTar_PageAlignment(t *testing.T) {
+	src, err := Open(InMemoryFileName, 100, 0644, WithPageAlignment(512))
+	require.NoError(t, err)
+	defer src.Close()
+
+	_, err = src.Alloc(2)
+	require.NoError(t, err)
+	require.NoError(t, src.Write(1, []byte("hello")))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, src.ExportTar(buf))
+
+	dst, err := ImportTar(buf, InMemoryFileName, 0644)
+	require.NoError(t, err)
+	defer dst.Close()
+
+	require.Equal(t, 100, dst.PageSize())
+	require.Equal(t, 512, dst.pageSize)
+	require.Equal(t, uint64(2), dst.Count())
+
+	data, err := dst.Read(1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), data[:5])
+}
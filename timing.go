@@ -0,0 +1,38 @@
+package pager
+
+import (
+	"sync"
+	"time"
+)
+
+// durationStats aggregates the durations of an operation, see WithTiming().
+type durationStats struct {
+	mu       sync.Mutex
+	n        int
+	total    time.Duration
+	min, max time.Duration
+}
+
+func (d *durationStats) add(dur time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.n == 0 || dur < d.min {
+		d.min = dur
+	}
+	d.max = max(d.max, dur)
+	d.total += dur
+	d.n++
+}
+
+// load returns the number of recorded durations and their minimum, average
+// and maximum, all zero if nothing has been recorded.
+func (d *durationStats) load() (n int, minDur, avgDur, maxDur time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.n == 0 {
+		return 0, 0, 0, 0
+	}
+	return d.n, d.min, d.total / time.Duration(d.n), d.max
+}
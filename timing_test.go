@@ -0,0 +1,32 @@
+package pager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_Timing(t *testing.T) {
+	p := OpenNop(16)
+	_, err := p.Alloc(1)
+	require.NoError(t, err)
+	require.Zero(t, p.Stats().AllocTruncates)
+
+	p, err = Open(InMemoryFileName, 16, 0644, WithTiming())
+	require.NoError(t, err)
+	defer p.Close()
+
+	for range 3 {
+		_, err := p.Alloc(2)
+		require.NoError(t, err)
+	}
+	require.NoError(t, p.Reserve(1))
+	_, err = p.Alloc(1)
+	require.NoError(t, err)
+
+	stats := p.Stats()
+	require.Equal(t, 3, stats.AllocTruncates)
+	require.LessOrEqual(t, stats.AllocTruncateMin, stats.AllocTruncateAvg)
+	require.LessOrEqual(t, stats.AllocTruncateAvg, stats.AllocTruncateMax)
+	require.Contains(t, stats.String(), "allocTruncates=3")
+}
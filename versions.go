@@ -0,0 +1,112 @@
+package pager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// pageVersionSize is the size of the page version stored at the end of each
+// page when WithPageVersions() is set.
+const pageVersionSize = 8
+
+// errNoVersions is returned by the versioned operations when page versions
+// are not enabled.
+var errNoVersions = errors.New("page versions are not enabled")
+
+// ReadVersioned reads the page with given id and returns its data together
+// with the version stored by WriteVersioned. Requires WithPageVersions().
+func (p *Pager) ReadVersioned(id uint64) (data []byte, version uint64, err error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.versions {
+		return nil, 0, errNoVersions
+	} else if id >= p.count {
+		return nil, 0, fmt.Errorf("invalid page id=%d (max=%d)", id, p.count-1)
+	} else if p.file == nil {
+		return nil, 0, os.ErrClosed
+	}
+
+	buf, err := p.readRaw(id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	size := p.usableSize()
+	return buf[:size:size], bin.Uint64(buf[size:]), nil
+}
+
+// WriteVersioned writes the data and the version to the page with given id
+// in a single write. Unlike Write, the bytes of the page not covered by the
+// data are zeroed. Requires WithPageVersions().
+func (p *Pager) WriteVersioned(id uint64, d []byte, version uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.versions {
+		return errNoVersions
+	} else if err := p.canWrite(id, len(d)); err != nil {
+		return err
+	}
+	return p.writeVersioned(id, d, version)
+}
+
+// CompareAndWrite writes the data with 'newVersion' to the page with given id
+// only if the version currently stored in the page equals 'expectedVersion'.
+// The check and the write happen atomically with respect to other pager
+// operations. Returns false without writing on version mismatch, in which
+// case the caller can re-read the page and retry. Requires
+// WithPageVersions().
+func (p *Pager) CompareAndWrite(id uint64, expectedVersion uint64, d []byte, newVersion uint64) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.versions {
+		return false, errNoVersions
+	} else if err := p.canWrite(id, len(d)); err != nil {
+		return false, err
+	}
+
+	buf, err := p.readRaw(id)
+	if err != nil {
+		return false, err
+	} else if bin.Uint64(buf[p.usableSize():]) != expectedVersion {
+		return false, nil
+	}
+
+	return true, p.writeVersioned(id, d, newVersion)
+}
+
+func (p *Pager) writeVersioned(id uint64, d []byte, version uint64) error {
+	buf := make([]byte, p.pageSize)
+	copy(buf, d)
+	bin.PutUint64(buf[p.usableSize():], version)
+	return p.writePage(id, buf)
+}
+
+// ChangedSince returns the ids of the pages whose stored version is greater
+// than 'version', e.g. to copy only the pages changed since the last
+// incremental backup. There is no index of versions: every call scans all
+// allocated pages, reading the whole file in chunks with the pager locked
+// shared. Requires WithPageVersions().
+func (p *Pager) ChangedSince(version uint64) ([]uint64, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.versions {
+		return nil, errNoVersions
+	}
+
+	changed := []uint64{}
+	size := p.usableSize()
+	err := p.scanPages(func(id uint64, page []byte) {
+		if bin.Uint64(page[size:]) > version {
+			changed = append(changed, id)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
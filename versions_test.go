@@ -0,0 +1,78 @@
+package pager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_PageVersions(t *testing.T) {
+	p, err := Open(InMemoryFileName, 32, 0644, WithPageVersions())
+	require.NoError(t, err)
+	defer p.Close()
+
+	require.Equal(t, 24, p.PageSize())
+	require.Equal(t, 8, p.Overhead().PageOverhead)
+
+	id, err := p.Alloc(1)
+	require.NoError(t, err)
+
+	require.NoError(t, p.WriteVersioned(id, []byte("data"), 42))
+	require.ErrorIs(t, p.WriteVersioned(id, make([]byte, 25), 43), ErrPageOverflow)
+
+	require.NoError(t, p.Write(id, []byte("new")))
+
+	data, version, err := p.ReadVersioned(id)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), version)
+	require.Equal(t, []byte("newa"), data[:4])
+
+	data, err = p.Read(id)
+	require.NoError(t, err)
+	require.Len(t, data, 24)
+}
+
+func TestPager_CompareAndWrite(t *testing.T) {
+	p, err := Open(InMemoryFileName, 32, 0644, WithPageVersions())
+	require.NoError(t, err)
+	defer p.Close()
+
+	id, err := p.Alloc(1)
+	require.NoError(t, err)
+
+	ok, err := p.CompareAndWrite(id, 0, []byte("first"), 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = p.CompareAndWrite(id, 0, []byte("stale"), 2)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	data, version, err := p.ReadVersioned(id)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), version)
+	require.Equal(t, []byte("first"), data[:5])
+}
+
+func TestPager_ChangedSince(t *testing.T) {
+	p, err := Open(InMemoryFileName, 32, 0644, WithPageVersions())
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(20)
+	require.NoError(t, err)
+	require.NoError(t, p.WriteVersioned(2, nil, 5))
+	require.NoError(t, p.WriteVersioned(7, nil, 10))
+	require.NoError(t, p.WriteVersioned(18, nil, 11))
+
+	changed, err := p.ChangedSince(5)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{7, 18}, changed)
+
+	changed, err = p.ChangedSince(11)
+	require.NoError(t, err)
+	require.Empty(t, changed)
+
+	_, err = OpenNop(32).ChangedSince(0)
+	require.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package pager
+
+import (
+	"context"
+	"os"
+)
+
+// Warm pulls the whole file into the OS page cache by reading all allocated
+// pages sequentially, or by touching the memory mapping when reads are
+// served from it. It's a no-op for pagers not backed by an os.File. The
+// reads don't count in Stats.
+func (p *Pager) Warm() error { return p.WarmContext(context.Background()) }
+
+// WarmContext is Warm stopping early with the context error when 'ctx' is
+// done. The pager is only locked while reading each chunk of pages, so
+// writers can make progress in between.
+func (p *Pager) WarmContext(ctx context.Context) error {
+	if !p.onDisk {
+		return nil
+	}
+
+	chunkPages := p.streamPages()
+	buf := p.buffers.get(chunkPages)
+	defer p.buffers.put(buf)
+
+	for id := uint64(0); ; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := p.warmChunk(buf, id, chunkPages)
+		if err != nil || n == 0 {
+			return err
+		}
+		id += uint64(n)
+	}
+}
+
+// warmChunk reads up to 'max' pages starting from 'id' into 'buf' and
+// returns the number of pages read, 0 past the last page.
+func (p *Pager) warmChunk(buf []byte, id uint64, max int) (int, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.file == nil {
+		return 0, os.ErrClosed
+	} else if id >= p.count {
+		return 0, nil
+	}
+
+	n := int(min(uint64(max), p.count-id))
+	chunk := buf[:n*p.pageSize]
+	if p.copyMapped(chunk, p.offset(id)) {
+		return n, nil
+	}
+
+	if m, err := p.readAt(chunk, p.offset(id)); m < len(chunk) {
+		return 0, shortRead(len(chunk), m, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,26 @@
+package pager
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager_Warm(t *testing.T) {
+	p, err := Open(filepath.Join(t.TempDir(), "pager.bin"), 16, 0644, WithStreamBufferSize(3))
+	require.NoError(t, err)
+	defer p.Close()
+
+	_, err = p.Alloc(10)
+	require.NoError(t, err)
+	require.NoError(t, p.Warm())
+	require.Zero(t, p.Stats().Reads)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, p.WarmContext(ctx), context.Canceled)
+
+	require.NoError(t, OpenNop(16).Warm())
+}